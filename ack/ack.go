@@ -0,0 +1,215 @@
+package ack
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+)
+
+//envelope is what's actually stored in the queue, it allows the number
+// of deliveries to follow the item when it's put back
+type envelope struct {
+	item       interface{}
+	deliveries int
+}
+
+type lease struct {
+	envelope *envelope
+	timer    *time.Timer
+	deadline time.Time
+}
+
+type queueAck struct {
+	sync.Mutex
+	configuration
+	storage Storage
+	closed  bool
+	receipt Receipt
+	leases  map[Receipt]*lease
+}
+
+//New can be used to create an ack queue on top of the given storage (e.g.
+// finite.New() or infinite.New()), the ack queue takes ownership of the
+// storage; items should only be enqueued and received via the ack queue
+func New(storage Storage, options ...Option) interface {
+	goqueue.Owner
+	goqueue.Enqueuer
+	goqueue.Length
+	goqueue.Event
+	Receiver
+} {
+	q := &queueAck{
+		configuration: configuration{
+			leaseTimeout: DefaultLeaseTimeout,
+		},
+		storage: storage,
+		leases:  make(map[Receipt]*lease),
+	}
+	for _, option := range options {
+		option(&q.configuration)
+	}
+	return q
+}
+
+//unwrap will convert an item from storage back into the item that was
+// enqueued, it's tolerant of items that were enqueued into storage directly
+func unwrap(item interface{}) *envelope {
+	if e, ok := item.(*envelope); ok {
+		return e
+	}
+	return &envelope{item: item}
+}
+
+func unwrapMultiple(items []interface{}) []interface{} {
+	for i, item := range items {
+		items[i] = unwrap(item).item
+	}
+	return items
+}
+
+//expire is executed by the lease timer, if the item can't be put back
+// because the storage is full, the lease is renewed
+func (q *queueAck) expire(receipt Receipt) {
+	q.Lock()
+	defer q.Unlock()
+
+	l, ok := q.leases[receipt]
+	if !ok || q.closed {
+		return
+	}
+	if overflow := q.storage.EnqueueInFront(l.envelope); overflow {
+		l.deadline = time.Now().Add(q.leaseTimeout)
+		l.timer.Reset(q.leaseTimeout)
+		return
+	}
+	delete(q.leases, receipt)
+}
+
+//Close will close the storage and return the items in the queue, items
+// that are leased are returned first (in the order they were received)
+func (q *queueAck) Close() (remainingElements []interface{}) {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.closed {
+		return
+	}
+	receipts := make([]Receipt, 0, len(q.leases))
+	for receipt, l := range q.leases {
+		l.timer.Stop()
+		receipts = append(receipts, receipt)
+	}
+	sort.Slice(receipts, func(i, j int) bool {
+		return receipts[i] < receipts[j]
+	})
+	for _, receipt := range receipts {
+		remainingElements = append(remainingElements, q.leases[receipt].envelope.item)
+	}
+	remainingElements = append(remainingElements, unwrapMultiple(q.storage.Close())...)
+	q.leases, q.closed = nil, true
+
+	return
+}
+
+func (q *queueAck) GetSignalIn() (signal <-chan struct{}) {
+	return q.storage.GetSignalIn()
+}
+
+func (q *queueAck) GetSignalOut() (signal <-chan struct{}) {
+	return q.storage.GetSignalOut()
+}
+
+func (q *queueAck) Enqueue(item interface{}) (overflow bool) {
+	return q.storage.Enqueue(&envelope{item: item})
+}
+
+func (q *queueAck) EnqueueMultiple(items []interface{}) (remainingElements []interface{}, overflow bool) {
+	envelopes := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		envelopes = append(envelopes, &envelope{item: item})
+	}
+	remainingElements, overflow = q.storage.EnqueueMultiple(envelopes)
+	return unwrapMultiple(remainingElements), overflow
+}
+
+//Length will return the number of items in the queue that aren't leased
+func (q *queueAck) Length() (size int) {
+	return q.storage.Length()
+}
+
+func (q *queueAck) Receive() (delivery Delivery, underflow bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.closed {
+		return Delivery{}, true
+	}
+	item, underflow := q.storage.Dequeue()
+	if underflow {
+		return Delivery{}, true
+	}
+	e := unwrap(item)
+	e.deliveries++
+	q.receipt++
+	receipt := q.receipt
+	l := &lease{
+		envelope: e,
+		deadline: time.Now().Add(q.leaseTimeout),
+		timer: time.AfterFunc(q.leaseTimeout, func() {
+			q.expire(receipt)
+		}),
+	}
+	q.leases[receipt] = l
+
+	return Delivery{
+		Item:       e.item,
+		Receipt:    receipt,
+		Deliveries: e.deliveries,
+		Deadline:   l.deadline,
+	}, false
+}
+
+func (q *queueAck) Ack(receipt Receipt) (err error) {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.closed {
+		return goqueue.ErrQueueClosed
+	}
+	l, ok := q.leases[receipt]
+	if !ok {
+		return ErrReceiptNotFound
+	}
+	l.timer.Stop()
+	delete(q.leases, receipt)
+
+	return
+}
+
+func (q *queueAck) Nack(receipt Receipt) (err error) {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.closed {
+		return goqueue.ErrQueueClosed
+	}
+	l, ok := q.leases[receipt]
+	if !ok {
+		return ErrReceiptNotFound
+	}
+	if overflow := q.storage.EnqueueInFront(l.envelope); overflow {
+		return ErrRequeueOverflow
+	}
+	l.timer.Stop()
+	delete(q.leases, receipt)
+
+	return
+}
+
+func (q *queueAck) InFlight() (n int) {
+	q.Lock()
+	defer q.Unlock()
+	return len(q.leases)
+}
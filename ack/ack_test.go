@@ -0,0 +1,164 @@
+package ack_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+	ack "github.com/antonio-alexander/go-queue/ack"
+	finite "github.com/antonio-alexander/go-queue/finite"
+	infinite "github.com/antonio-alexander/go-queue/infinite"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	casef       string = "case: %s"
+	mustTimeout        = time.Second
+)
+
+var storages = map[string]func(size int) ack.Storage{
+	"finite": func(size int) ack.Storage {
+		return finite.New(size)
+	},
+	"infinite": func(size int) ack.Storage {
+		return infinite.New(size)
+	},
+}
+
+func TestAckQueue(t *testing.T) {
+	t.Run("Ack", func(t *testing.T) {
+		for cDesc, newStorage := range storages {
+			q := ack.New(newStorage(10))
+			assert.False(t, q.Enqueue(1), casef, cDesc)
+			delivery, underflow := q.Receive()
+			assert.False(t, underflow, casef, cDesc)
+			assert.Equal(t, 1, delivery.Item, casef, cDesc)
+			assert.Equal(t, 1, delivery.Deliveries, casef, cDesc)
+			assert.Equal(t, 1, q.InFlight(), casef, cDesc)
+			assert.Zero(t, q.Length(), casef, cDesc)
+			assert.NoError(t, q.Ack(delivery.Receipt), casef, cDesc)
+			assert.ErrorIs(t, q.Ack(delivery.Receipt), ack.ErrReceiptNotFound, casef, cDesc)
+			assert.ErrorIs(t, q.Nack(delivery.Receipt), ack.ErrReceiptNotFound, casef, cDesc)
+			assert.Zero(t, q.InFlight(), casef, cDesc)
+			_, underflow = q.Receive()
+			assert.True(t, underflow, casef, cDesc)
+			assert.Empty(t, q.Close(), casef, cDesc)
+		}
+	})
+	t.Run("Nack", func(t *testing.T) {
+		for cDesc, newStorage := range storages {
+			q := ack.New(newStorage(10))
+			_, overflow := q.EnqueueMultiple([]interface{}{1, 2, 3})
+			assert.False(t, overflow, casef, cDesc)
+			delivery, _ := q.Receive()
+			assert.Equal(t, 1, delivery.Item, casef, cDesc)
+			assert.NoError(t, q.Nack(delivery.Receipt), casef, cDesc)
+			assert.ErrorIs(t, q.Ack(delivery.Receipt), ack.ErrReceiptNotFound, casef, cDesc)
+			redelivery, underflow := q.Receive()
+			assert.False(t, underflow, casef, cDesc)
+			assert.Equal(t, 1, redelivery.Item, casef, cDesc)
+			assert.Equal(t, 2, redelivery.Deliveries, casef, cDesc)
+			assert.NotEqual(t, delivery.Receipt, redelivery.Receipt, casef, cDesc)
+			assert.NoError(t, q.Ack(redelivery.Receipt), casef, cDesc)
+			assert.Equal(t, []interface{}{2, 3}, q.Close(), casef, cDesc)
+		}
+	})
+	t.Run("Lease Expired", func(t *testing.T) {
+		const leaseTimeout = 10 * time.Millisecond
+
+		for cDesc, newStorage := range storages {
+			q := ack.New(newStorage(10), ack.WithLeaseTimeout(leaseTimeout))
+			q.Enqueue(1)
+			q.Enqueue(2)
+			signalIn := q.GetSignalIn()
+			for len(signalIn) > 0 {
+				<-signalIn
+			}
+			delivery, _ := q.Receive()
+			assert.Equal(t, 1, delivery.Item, casef, cDesc)
+			assert.WithinDuration(t, time.Now().Add(leaseTimeout), delivery.Deadline, leaseTimeout, casef, cDesc)
+			assert.Eventually(t, func() bool {
+				return q.InFlight() == 0
+			}, mustTimeout, time.Millisecond, casef, cDesc)
+			assert.ErrorIs(t, q.Ack(delivery.Receipt), ack.ErrReceiptNotFound, casef, cDesc)
+			redelivery, underflow := q.Receive()
+			assert.False(t, underflow, casef, cDesc)
+			assert.Equal(t, 1, redelivery.Item, casef, cDesc)
+			assert.Equal(t, 2, redelivery.Deliveries, casef, cDesc)
+			assert.NoError(t, q.Ack(redelivery.Receipt), casef, cDesc)
+			q.Close()
+		}
+	})
+	t.Run("Requeue Overflow", func(t *testing.T) {
+		q := ack.New(finite.New(1))
+		defer q.Close()
+		q.Enqueue(1)
+		delivery, _ := q.Receive()
+		assert.False(t, q.Enqueue(2))
+		assert.ErrorIs(t, q.Nack(delivery.Receipt), ack.ErrRequeueOverflow)
+		assert.Equal(t, 1, q.InFlight())
+		assert.NoError(t, q.Ack(delivery.Receipt))
+	})
+	t.Run("Close", func(t *testing.T) {
+		for cDesc, newStorage := range storages {
+			q := ack.New(newStorage(10))
+			q.EnqueueMultiple([]interface{}{1, 2, 3, 4})
+			first, _ := q.Receive()
+			q.Receive()
+			assert.Equal(t, []interface{}{1, 2, 3, 4}, q.Close(), casef, cDesc)
+			assert.ErrorIs(t, q.Ack(first.Receipt), goqueue.ErrQueueClosed, casef, cDesc)
+			_, underflow := q.Receive()
+			assert.True(t, underflow, casef, cDesc)
+			assert.Empty(t, q.Close(), casef, cDesc)
+		}
+	})
+	t.Run("Multiple Consumers", func(t *testing.T) {
+		//KIM: every other delivery is negatively acknowledged, once all of the
+		// items have been acknowledged each item should have been
+		// acknowledged exactly once
+		const nConsumers, nItems = 4, 250
+
+		for cDesc, newStorage := range storages {
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+
+			q := ack.New(newStorage(nItems))
+			for i := 0; i < nItems; i++ {
+				q.Enqueue(i)
+			}
+			acked := make(map[interface{}]int)
+			for i := 0; i < nConsumers; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for {
+						delivery, underflow := q.Receive()
+						if underflow {
+							if q.InFlight() == 0 {
+								return
+							}
+							continue
+						}
+						if delivery.Deliveries%2 == 1 {
+							assert.NoError(t, q.Nack(delivery.Receipt))
+							continue
+						}
+						if assert.NoError(t, q.Ack(delivery.Receipt)) {
+							mu.Lock()
+							acked[delivery.Item]++
+							mu.Unlock()
+						}
+					}
+				}()
+			}
+			wg.Wait()
+			assert.Len(t, acked, nItems, casef, cDesc)
+			for item, n := range acked {
+				assert.Equal(t, 1, n, "case: %s, item: %v", cDesc, item)
+			}
+			q.Close()
+		}
+	})
+}
@@ -0,0 +1,12 @@
+// Copyright 2022 antonio-alexander. All rights reserved.
+// Use of this source code is governed by an MIT
+// license that can be found in the LICENSE file.
+
+/*
+	Package ack provides a queue that wraps another queue (e.g. finite or
+	infinite) to support acknowledged consumption: items are leased to a
+	consumer and are only removed once they're acknowledged, if they're
+	negatively acknowledged (or the lease expires) they're put back at the
+	front of the queue
+*/
+package ack
@@ -0,0 +1,77 @@
+package ack
+
+import (
+	"errors"
+	"time"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+)
+
+//DefaultLeaseTimeout is how long an item is leased to a consumer if no
+// lease timeout is configured
+const DefaultLeaseTimeout = 30 * time.Second
+
+//ErrReceiptNotFound will be returned if a receipt is acknowledged (or
+// negatively acknowledged) after its lease has expired or it's already
+// been acknowledged
+var ErrReceiptNotFound = errors.New("receipt not found")
+
+//ErrRequeueOverflow will be returned by Nack() if the item can't be put
+// back in the queue because the queue is full, the item remains leased
+// and will be put back when the lease expires (if there's room)
+var ErrRequeueOverflow = errors.New("unable to requeue item: overflow")
+
+//Receipt uniquely identifies the lease of an item, it's only valid until
+// the item is acknowledged or its lease expires
+type Receipt uint64
+
+//Delivery describes an item that's been leased to a consumer
+type Delivery struct {
+	Item       interface{}
+	Receipt    Receipt
+	Deliveries int
+	Deadline   time.Time
+}
+
+//Receiver can be used to consume items from a queue without losing them
+// if the work done on them fails. Receive() will lease the item at the front
+// of the queue; the item is removed once Ack() is called with its receipt
+// and put back at the front of the queue if Nack() is called or the lease
+// expires. Deliveries is the number of times the item has been received
+// (including this one). InFlight() returns the number of leased items
+type Receiver interface {
+	Receive() (delivery Delivery, underflow bool)
+	Ack(receipt Receipt) (err error)
+	Nack(receipt Receipt) (err error)
+	InFlight() (n int)
+}
+
+//Storage describes the queue the ack queue is built on top of, both the
+// finite and infinite queues satisfy this interface
+type Storage interface {
+	goqueue.Owner
+	goqueue.Dequeuer
+	goqueue.Enqueuer
+	goqueue.EnqueueInFronter
+	goqueue.Length
+	goqueue.Event
+}
+
+//Option can be used to configure an ack queue on creation
+type Option func(*configuration)
+
+type configuration struct {
+	leaseTimeout time.Duration
+}
+
+//WithLeaseTimeout will configure how long an item is leased to a consumer
+// before it's put back in the queue, if the timeout isn't greater than zero
+// the DefaultLeaseTimeout is used
+func WithLeaseTimeout(timeout time.Duration) Option {
+	return func(c *configuration) {
+		if timeout <= 0 {
+			timeout = DefaultLeaseTimeout
+		}
+		c.leaseTimeout = timeout
+	}
+}
@@ -0,0 +1,94 @@
+package batch
+
+import (
+	"context"
+	"time"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+)
+
+type batcher struct {
+	configuration
+	queue interface {
+		goqueue.Dequeuer
+		goqueue.Event
+	}
+	handler Handler
+}
+
+//New can be used to create a batcher that consumes items from the given
+// queue and calls the handler with each batch
+func New(queue interface {
+	goqueue.Dequeuer
+	goqueue.Event
+}, handler Handler, options ...Option) Batcher {
+	b := &batcher{
+		configuration: configuration{
+			maxSize: DefaultMaxSize,
+			maxWait: DefaultMaxWait,
+		},
+		queue:   queue,
+		handler: handler,
+	}
+	for _, option := range options {
+		option(&b.configuration)
+	}
+	return b
+}
+
+//drain will handle the given batch (if any) along with the items that
+// remain in the queue in batches of max size, it stops once the queue is
+// empty such that it can't be held up by a producer
+func (b *batcher) drain(batch []interface{}) {
+	for {
+		batch = append(batch, b.queue.DequeueMultiple(b.maxSize-len(batch))...)
+		if len(batch) <= 0 {
+			return
+		}
+		full := len(batch) >= b.maxSize
+		b.handler(batch)
+		if !full {
+			return
+		}
+		batch = nil
+	}
+}
+
+//Run will consume items from the queue until the context is done, items
+// will wait at most max wait to be handled; the queue is polled at max wait
+// in case a signal was missed
+func (b *batcher) Run(ctx context.Context) {
+	var batch []interface{}
+
+	signalIn := b.queue.GetSignalIn()
+	tFlush := time.NewTicker(b.maxWait)
+	defer tFlush.Stop()
+	for {
+		if batch = append(batch, b.queue.DequeueMultiple(b.maxSize-len(batch))...); len(batch) >= b.maxSize {
+			b.handler(batch)
+			batch = nil
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			b.drain(batch)
+			return
+		case _, ok := <-signalIn:
+			if !ok {
+				//KIM: the signal is closed if the queue is closed or
+				// re-sized, if it's the same signal, stop listening
+				// and rely on polling
+				if signal := b.queue.GetSignalIn(); signal != signalIn {
+					signalIn = signal
+				} else {
+					signalIn = nil
+				}
+			}
+		case <-tFlush.C:
+			if len(batch) > 0 {
+				b.handler(batch)
+				batch = nil
+			}
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package batch_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	batch "github.com/antonio-alexander/go-queue/batch"
+	finite "github.com/antonio-alexander/go-queue/finite"
+	infinite "github.com/antonio-alexander/go-queue/infinite"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	mustTimeout = time.Second
+	mustRate    = time.Millisecond
+	casef       = "case: %s"
+)
+
+//handler can be used to record the batches that have been handled
+type handler struct {
+	sync.Mutex
+	batches [][]interface{}
+}
+
+func (h *handler) handle(items []interface{}) {
+	h.Lock()
+	defer h.Unlock()
+	h.batches = append(h.batches, items)
+}
+
+func (h *handler) get() [][]interface{} {
+	h.Lock()
+	defer h.Unlock()
+	return append([][]interface{}{}, h.batches...)
+}
+
+func TestBatcher(t *testing.T) {
+	t.Run("Test Max Size", func(t *testing.T) {
+		var wg sync.WaitGroup
+
+		//enqueue more items than fit in a batch and confirm that full
+		// batches are handled without waiting
+		h := &handler{}
+		q := finite.New(10)
+		defer q.Close()
+		b := batch.New(q, h.handle, batch.WithMaxSize(3), batch.WithMaxWait(time.Hour))
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.Run(ctx)
+		}()
+		for i := 0; i < 7; i++ {
+			assert.False(t, q.Enqueue(i))
+		}
+		assert.Eventually(t, func() bool { return len(h.get()) == 2 }, mustTimeout, mustRate)
+		assert.Equal(t, [][]interface{}{{0, 1, 2}, {3, 4, 5}}, h.get())
+
+		//stop the batcher and confirm that the last item is drained
+		cancel()
+		wg.Wait()
+		assert.Equal(t, [][]interface{}{{0, 1, 2}, {3, 4, 5}, {6}}, h.get())
+	})
+	t.Run("Test Max Wait", func(t *testing.T) {
+		const maxWait = 10 * time.Millisecond
+
+		var wg sync.WaitGroup
+
+		//enqueue fewer items than fit in a batch and confirm that they're
+		// handled once the max wait has passed
+		h := &handler{}
+		q := infinite.New(10)
+		defer q.Close()
+		b := batch.New(q, h.handle, batch.WithMaxSize(100), batch.WithMaxWait(maxWait))
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.Run(ctx)
+		}()
+		assert.False(t, q.Enqueue(0))
+		assert.False(t, q.Enqueue(1))
+		assert.Eventually(t, func() bool { return len(h.get()) == 1 }, mustTimeout, mustRate)
+		assert.Equal(t, [][]interface{}{{0, 1}}, h.get())
+		cancel()
+		wg.Wait()
+		assert.Len(t, h.get(), 1)
+	})
+	t.Run("Test Drain", func(t *testing.T) {
+		cases := map[string]struct {
+			iItems   int
+			iMaxSize int
+			oBatches []int
+		}{
+			"empty": {
+				iMaxSize: 10,
+			},
+			"partial": {
+				iItems:   5,
+				iMaxSize: 10,
+				oBatches: []int{5},
+			},
+			"multiple": {
+				iItems:   25,
+				iMaxSize: 10,
+				oBatches: []int{10, 10, 5},
+			},
+			"exact": {
+				iItems:   20,
+				iMaxSize: 10,
+				oBatches: []int{10, 10},
+			},
+		}
+		for cDesc, c := range cases {
+			//enqueue items and run the batcher with a context that's
+			// already done, confirm that the queue is drained in batches
+			h := &handler{}
+			q := finite.New(100)
+			for i := 0; i < c.iItems; i++ {
+				assert.False(t, q.Enqueue(i), casef, cDesc)
+			}
+			ctx, cancel := context.WithCancel(context.TODO())
+			cancel()
+			batch.New(q, h.handle, batch.WithMaxSize(c.iMaxSize)).Run(ctx)
+			var sizes []int
+			n := 0
+			for _, items := range h.get() {
+				sizes = append(sizes, len(items))
+				for _, item := range items {
+					assert.Equal(t, n, item, casef, cDesc)
+					n++
+				}
+			}
+			assert.Equal(t, c.oBatches, sizes, casef, cDesc)
+			assert.Empty(t, q.Close(), casef, cDesc)
+		}
+	})
+	t.Run("Test Close", func(t *testing.T) {
+		var wg sync.WaitGroup
+
+		//close the queue while the batcher is running and confirm that
+		// it continues to run until the context is done
+		h := &handler{}
+		q := finite.New(10)
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			batch.New(q, h.handle, batch.WithMaxWait(mustRate)).Run(ctx)
+		}()
+		q.Close()
+		time.Sleep(10 * mustRate)
+		cancel()
+		wg.Wait()
+		assert.Empty(t, h.get())
+	})
+}
@@ -0,0 +1,9 @@
+// Copyright 2022 antonio-alexander. All rights reserved.
+// Use of this source code is governed by an MIT
+// license that can be found in the LICENSE file.
+
+/*
+	Package batch provides a consumer that dequeues items from a queue in
+	batches, a batch is handled once it's full or it's waited long enough
+*/
+package batch
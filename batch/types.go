@@ -0,0 +1,56 @@
+package batch
+
+import (
+	"context"
+	"time"
+)
+
+//DefaultMaxSize is the most items in a batch if no max size is configured
+const DefaultMaxSize = 100
+
+//DefaultMaxWait is the longest items will wait in a batch if no max wait
+// is configured
+const DefaultMaxWait = time.Second
+
+//Handler is called with each batch of items, it's never called with an
+// empty batch and the items are owned by the handler
+type Handler func(items []interface{})
+
+//Batcher can be used to consume items from a queue in batches. Run() will
+// dequeue items and call the handler once it has max size items or max wait
+// has passed (whichever comes first) until the context is done; once done,
+// it will drain the queue (in batches) and return
+type Batcher interface {
+	Run(ctx context.Context)
+}
+
+//Option can be used to configure a batcher on creation
+type Option func(*configuration)
+
+type configuration struct {
+	maxSize int
+	maxWait time.Duration
+}
+
+//WithMaxSize will configure the most items in a batch, if the size is less
+// than one, the DefaultMaxSize is used
+func WithMaxSize(size int) Option {
+	return func(c *configuration) {
+		if size < 1 {
+			size = DefaultMaxSize
+		}
+		c.maxSize = size
+	}
+}
+
+//WithMaxWait will configure the longest items will wait in a batch before
+// it's handled, if the wait isn't greater than zero, the DefaultMaxWait is
+// used
+func WithMaxWait(wait time.Duration) Option {
+	return func(c *configuration) {
+		if wait <= 0 {
+			wait = DefaultMaxWait
+		}
+		c.maxWait = wait
+	}
+}
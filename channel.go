@@ -0,0 +1,211 @@
+package goqueue
+
+import (
+	"context"
+	"time"
+)
+
+//enqueueLossy mirrors finite.EnqueueLossy, it can't be imported since the
+// finite package depends on this package
+type enqueueLossy interface {
+	EnqueueLossy(item interface{}) (discardedElement interface{}, discard bool)
+}
+
+func newChannelConfiguration(options ...ChannelOption) channelConfiguration {
+	c := channelConfiguration{
+		overflow: ChannelBlock,
+		rate:     DefaultChannelRate,
+	}
+	for _, option := range options {
+		option(&c)
+	}
+	return c
+}
+
+//reacquire will get the signal again if it's been closed, ok will be false
+// if the queue has been closed rather than re-created (e.g. re-sized)
+func reacquire(closed <-chan struct{}, getSignal func() <-chan struct{}) (signal <-chan struct{}, ok bool) {
+	if signal = getSignal(); signal == nil || signal == closed {
+		return nil, false
+	}
+	return signal, true
+}
+
+//ToChannel will start a pump that dequeues items from the queue and sends
+// them to the returned channel until the context is done or the queue is
+// closed (its signal in is closed). Once the pump stops, the channel will
+// be closed and any item that was dequeued, but not delivered will be sent
+// to the undelivered channel (which will then be closed). If the channel is
+// full, the item will be handled according to the configured overflow; for
+// ChannelLossy, the oldest item in the channel will be discarded (if the
+// channel isn't buffered, the item is dropped)
+func ToChannel(ctx context.Context, queue interface {
+	Dequeuer
+	Event
+}, options ...ChannelOption) (items <-chan interface{}, undelivered <-chan []interface{}) {
+	c := newChannelConfiguration(options...)
+	chItems := make(chan interface{}, c.buffer)
+	chUndelivered := make(chan []interface{}, 1)
+	go func() {
+		var remaining []interface{}
+
+		defer func() {
+			close(chItems)
+			chUndelivered <- remaining
+			close(chUndelivered)
+		}()
+		signalIn := queue.GetSignalIn()
+		tPoll := time.NewTicker(c.rate)
+		defer tPoll.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+			item, underflow := queue.Dequeue()
+			if underflow {
+				select {
+				case <-ctx.Done():
+					return
+				case _, ok := <-signalIn:
+					if ok {
+						break
+					}
+					if signalIn, ok = reacquire(signalIn, queue.GetSignalIn); !ok {
+						return
+					}
+				case <-tPoll.C:
+					//KIM: a queue may drain its signal rather than
+					// close it when it's closed
+					if signalIn = queue.GetSignalIn(); signalIn == nil {
+						return
+					}
+				}
+				continue
+			}
+			switch c.overflow {
+			default:
+				select {
+				case <-ctx.Done():
+					remaining = []interface{}{item}
+					return
+				case chItems <- item:
+				}
+			case ChannelDrop:
+				select {
+				default:
+				case chItems <- item:
+				}
+			case ChannelLossy:
+				for sent := false; !sent; {
+					select {
+					case chItems <- item:
+						sent = true
+					default:
+						select {
+						default:
+							sent = true
+						case <-chItems:
+						}
+					}
+				}
+			}
+		}
+	}()
+	return chItems, chUndelivered
+}
+
+//FromChannel will start a pump that receives items from the channel and
+// enqueues them into the queue until the context is done or the channel is
+// closed. Once the pump stops, any items that were received but couldn't be
+// enqueued along with any items buffered in the channel will be sent to the
+// undelivered channel (which will then be closed). If the queue is full,
+// the item will be handled according to the configured overflow; for
+// ChannelBlock, the pump will wait on the queue's signal out (if it's an
+// Event) or poll at the configured rate and for ChannelLossy the queue
+// must implement EnqueueLossy() otherwise the item is dropped
+func FromChannel(ctx context.Context, items <-chan interface{}, queue Enqueuer, options ...ChannelOption) (undelivered <-chan []interface{}) {
+	c := newChannelConfiguration(options...)
+	chUndelivered := make(chan []interface{}, 1)
+	go func() {
+		var remaining []interface{}
+		var signalOut <-chan struct{}
+		var getSignalOut func() <-chan struct{}
+
+		defer func() {
+			chUndelivered <- remaining
+			close(chUndelivered)
+		}()
+		//drain will receive any items that are buffered in the channel
+		// without blocking
+		drain := func() {
+			for {
+				select {
+				default:
+					return
+				case item, ok := <-items:
+					if !ok {
+						return
+					}
+					remaining = append(remaining, item)
+				}
+			}
+		}
+		if event, ok := queue.(Event); ok {
+			getSignalOut = event.GetSignalOut
+			signalOut = getSignalOut()
+		}
+		tPoll := time.NewTicker(c.rate)
+		defer tPoll.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				drain()
+				return
+			case item, ok := <-items:
+				if !ok {
+					return
+				}
+				switch c.overflow {
+				default:
+					for queue.Enqueue(item) {
+						select {
+						case <-ctx.Done():
+							remaining = append(remaining, item)
+							drain()
+							return
+						case _, ok := <-signalOut:
+							if ok {
+								break
+							}
+							if signalOut, ok = reacquire(signalOut, getSignalOut); !ok {
+								remaining = append(remaining, item)
+								drain()
+								return
+							}
+						case <-tPoll.C:
+							if getSignalOut == nil {
+								break
+							}
+							if signalOut = getSignalOut(); signalOut == nil {
+								remaining = append(remaining, item)
+								drain()
+								return
+							}
+						}
+					}
+				case ChannelDrop:
+					queue.Enqueue(item)
+				case ChannelLossy:
+					if queue, ok := queue.(enqueueLossy); ok {
+						queue.EnqueueLossy(item)
+						continue
+					}
+					queue.Enqueue(item)
+				}
+			}
+		}
+	}()
+	return chUndelivered
+}
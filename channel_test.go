@@ -0,0 +1,215 @@
+package goqueue_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+	finite "github.com/antonio-alexander/go-queue/finite"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	mustTimeout = time.Second
+	mustRate    = time.Millisecond
+	casef       = "case: %s"
+)
+
+func TestToChannel(t *testing.T) {
+	t.Run("Test Block", func(t *testing.T) {
+		//enqueue items and confirm that they're received in order
+		q := finite.New(10)
+		defer q.Close()
+		for i := 0; i < 10; i++ {
+			assert.False(t, q.Enqueue(i))
+		}
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+		items, undelivered := goqueue.ToChannel(ctx, q)
+		for i := 0; i < 10; i++ {
+			select {
+			case <-time.After(mustTimeout):
+				assert.Fail(t, "no item received when expected")
+			case item := <-items:
+				assert.Equal(t, i, item)
+			}
+		}
+
+		//enqueue an item after the pump is waiting and confirm that
+		// it's received
+		assert.False(t, q.Enqueue(10))
+		select {
+		case <-time.After(mustTimeout):
+			assert.Fail(t, "no item received when expected")
+		case item := <-items:
+			assert.Equal(t, 10, item)
+		}
+
+		//stop the pump and confirm that nothing was undelivered
+		cancel()
+		assert.Empty(t, <-undelivered)
+		_, ok := <-items
+		assert.False(t, ok)
+	})
+	t.Run("Test Undelivered", func(t *testing.T) {
+		//receive a single item and wait for the pump to dequeue the
+		// next item, confirm that it's returned when the pump stops
+		q := finite.New(3)
+		defer q.Close()
+		for i := 0; i < 3; i++ {
+			assert.False(t, q.Enqueue(i))
+		}
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+		items, undelivered := goqueue.ToChannel(ctx, q)
+		assert.Equal(t, 0, <-items)
+		assert.Eventually(t, func() bool { return q.Length() == 1 }, mustTimeout, mustRate)
+		cancel()
+		assert.Equal(t, []interface{}{1}, <-undelivered)
+		assert.Equal(t, []interface{}{2}, q.Flush())
+	})
+	t.Run("Test Overflow", func(t *testing.T) {
+		cases := map[string]struct {
+			iOverflow goqueue.ChannelOverflow
+			oItems    []interface{}
+		}{
+			"drop": {
+				iOverflow: goqueue.ChannelDrop,
+				oItems:    []interface{}{0},
+			},
+			"lossy": {
+				iOverflow: goqueue.ChannelLossy,
+				oItems:    []interface{}{2},
+			},
+		}
+		for cDesc, c := range cases {
+			//enqueue more items than the channel can hold and once the
+			// queue is empty, confirm which items are in the channel
+			q := finite.New(3)
+			for i := 0; i < 3; i++ {
+				assert.False(t, q.Enqueue(i))
+			}
+			ctx, cancel := context.WithCancel(context.TODO())
+			items, undelivered := goqueue.ToChannel(ctx, q,
+				goqueue.WithChannelOverflow(c.iOverflow),
+				goqueue.WithChannelBuffer(1))
+			assert.Eventually(t, func() bool { return q.Length() == 0 }, mustTimeout, mustRate, casef, cDesc)
+			cancel()
+			assert.Empty(t, <-undelivered, casef, cDesc)
+			var received []interface{}
+			for item := range items {
+				received = append(received, item)
+			}
+			assert.Equal(t, c.oItems, received, casef, cDesc)
+			q.Close()
+		}
+	})
+	t.Run("Test Close", func(t *testing.T) {
+		//close the queue and confirm that the pump stops
+		q := finite.New(1)
+		items, undelivered := goqueue.ToChannel(context.TODO(), q)
+		q.Close()
+		select {
+		case <-time.After(mustTimeout):
+			assert.Fail(t, "channel not closed when expected")
+		case _, ok := <-items:
+			assert.False(t, ok)
+		}
+		assert.Empty(t, <-undelivered)
+	})
+}
+
+func TestFromChannel(t *testing.T) {
+	t.Run("Test Block", func(t *testing.T) {
+		//send items and confirm that they're enqueued in order, then
+		// close the channel and confirm the pump stops
+		q := finite.New(10)
+		defer q.Close()
+		items := make(chan interface{})
+		undelivered := goqueue.FromChannel(context.TODO(), items, q)
+		for i := 0; i < 10; i++ {
+			items <- i
+		}
+		close(items)
+		assert.Empty(t, <-undelivered)
+		assert.Equal(t, []interface{}{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, q.Flush())
+	})
+	t.Run("Test Wait", func(t *testing.T) {
+		//fill the queue and confirm that the pump waits until there's
+		// room in the queue
+		q := finite.New(1)
+		defer q.Close()
+		items := make(chan interface{}, 2)
+		items <- 0
+		items <- 1
+		undelivered := goqueue.FromChannel(context.TODO(), items, q)
+		assert.Eventually(t, func() bool { return len(items) == 0 }, mustTimeout, mustRate)
+		item, underflow := q.Dequeue()
+		assert.False(t, underflow)
+		assert.Equal(t, 0, item)
+		assert.Eventually(t, func() bool { return q.Length() == 1 }, mustTimeout, mustRate)
+		close(items)
+		assert.Empty(t, <-undelivered)
+		assert.Equal(t, []interface{}{1}, q.Flush())
+	})
+	t.Run("Test Undelivered", func(t *testing.T) {
+		//fill the queue such that the pump is waiting with an item and
+		// confirm that it and the items in the channel are returned
+		q := finite.New(1)
+		defer q.Close()
+		items := make(chan interface{}, 3)
+		for i := 0; i < 3; i++ {
+			items <- i
+		}
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+		undelivered := goqueue.FromChannel(ctx, items, q)
+		assert.Eventually(t, func() bool { return len(items) == 1 }, mustTimeout, mustRate)
+		cancel()
+		assert.Equal(t, []interface{}{1, 2}, <-undelivered)
+		assert.Equal(t, []interface{}{0}, q.Flush())
+	})
+	t.Run("Test Overflow", func(t *testing.T) {
+		cases := map[string]struct {
+			iOverflow goqueue.ChannelOverflow
+			oItems    []interface{}
+		}{
+			"drop": {
+				iOverflow: goqueue.ChannelDrop,
+				oItems:    []interface{}{0},
+			},
+			"lossy": {
+				iOverflow: goqueue.ChannelLossy,
+				oItems:    []interface{}{2},
+			},
+		}
+		for cDesc, c := range cases {
+			//send more items than the queue can hold and confirm which
+			// items are in the queue
+			q := finite.New(1)
+			items := make(chan interface{}, 3)
+			for i := 0; i < 3; i++ {
+				items <- i
+			}
+			close(items)
+			undelivered := goqueue.FromChannel(context.TODO(), items, q,
+				goqueue.WithChannelOverflow(c.iOverflow))
+			assert.Empty(t, <-undelivered, casef, cDesc)
+			assert.Equal(t, c.oItems, q.Close(), casef, cDesc)
+		}
+	})
+	t.Run("Test Close", func(t *testing.T) {
+		//close the queue while the pump is waiting and confirm that
+		// the item is returned
+		q := finite.New(1)
+		assert.False(t, q.Enqueue(0))
+		items := make(chan interface{}, 1)
+		items <- 1
+		undelivered := goqueue.FromChannel(context.TODO(), items, q)
+		assert.Eventually(t, func() bool { return len(items) == 0 }, mustTimeout, mustRate)
+		q.Close()
+		assert.Equal(t, []interface{}{1}, <-undelivered)
+	})
+}
@@ -0,0 +1,142 @@
+package deadletter
+
+import (
+	"time"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+)
+
+//envelope is what's actually stored in the queue, it allows the metadata
+// to follow the item when it's put back
+type envelope struct {
+	item interface{}
+	Metadata
+}
+
+type queueDeadLetter struct {
+	configuration
+	queue interface {
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	}
+	target goqueue.Enqueuer
+}
+
+//New can be used to wrap the given queue such that items that fail to be
+// processed via Attempt() more than the max attempts are moved to the target
+// queue, items should only be enqueued via the wrapper
+func New(queue interface {
+	goqueue.Enqueuer
+	goqueue.Dequeuer
+}, target goqueue.Enqueuer, options ...Option) interface {
+	goqueue.Owner
+	goqueue.Enqueuer
+	goqueue.Dequeuer
+	Attempter
+} {
+	q := &queueDeadLetter{
+		configuration: configuration{
+			maxAttempts: DefaultMaxAttempts,
+		},
+		queue:  queue,
+		target: target,
+	}
+	for _, option := range options {
+		option(&q.configuration)
+	}
+	return q
+}
+
+//unwrap will return the envelope for an item from the queue, it's tolerant
+// of items that were enqueued into the queue directly
+func unwrap(item interface{}) *envelope {
+	if e, ok := item.(*envelope); ok {
+		return e
+	}
+	return &envelope{item: item}
+}
+
+func unwrapMultiple(items []interface{}) []interface{} {
+	for i, item := range items {
+		items[i] = unwrap(item).item
+	}
+	return items
+}
+
+func (q *queueDeadLetter) requeue(e *envelope) (overflow bool) {
+	if eif, ok := q.queue.(goqueue.EnqueueInFronter); ok && q.requeueInFront {
+		return eif.EnqueueInFront(e)
+	}
+	return q.queue.Enqueue(e)
+}
+
+//Close will close the wrapped queue if it's an Owner and return the items
+// that remain, it won't close the dead-letter queue
+func (q *queueDeadLetter) Close() (remainingElements []interface{}) {
+	if owner, ok := q.queue.(goqueue.Owner); ok {
+		return unwrapMultiple(owner.Close())
+	}
+	return nil
+}
+
+func (q *queueDeadLetter) Attempt(fn func(item interface{}) error) (underflow bool, err error) {
+	item, underflow := q.queue.Dequeue()
+	if underflow {
+		return true, nil
+	}
+	e, now := unwrap(item), time.Now()
+	if e.FirstAttempt.IsZero() {
+		e.FirstAttempt = now
+	}
+	e.LastAttempt = now
+	if err = fn(e.item); err == nil {
+		return
+	}
+	e.Failures++
+	e.LastError = err
+	if e.Failures < q.maxAttempts {
+		if overflow := q.requeue(e); !overflow {
+			return
+		}
+		//KIM: if the item can't be put back, it's dead-lettered early
+		// rather than discarded
+		err = ErrRequeueOverflow
+	}
+	//KIM: if the item can't be dead-lettered, it's put back in the queue
+	// so that it's dead-lettered on its next attempt
+	if overflow := q.target.Enqueue(&DeadLetter{Item: e.item, Metadata: e.Metadata}); !overflow {
+		return
+	}
+	if overflow := q.queue.Enqueue(e); overflow {
+		return false, ErrDeadLetterOverflow
+	}
+	return
+}
+
+func (q *queueDeadLetter) Dequeue() (item interface{}, underflow bool) {
+	if item, underflow = q.queue.Dequeue(); underflow {
+		return
+	}
+	return unwrap(item).item, false
+}
+
+func (q *queueDeadLetter) DequeueMultiple(n int) (items []interface{}) {
+	return unwrapMultiple(q.queue.DequeueMultiple(n))
+}
+
+func (q *queueDeadLetter) Flush() (items []interface{}) {
+	return unwrapMultiple(q.queue.Flush())
+}
+
+func (q *queueDeadLetter) Enqueue(item interface{}) (overflow bool) {
+	return q.queue.Enqueue(&envelope{item: item})
+}
+
+func (q *queueDeadLetter) EnqueueMultiple(items []interface{}) (remainingElements []interface{}, overflow bool) {
+	envelopes := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		envelopes = append(envelopes, &envelope{item: item})
+	}
+	remainingElements, overflow = q.queue.EnqueueMultiple(envelopes)
+	return unwrapMultiple(remainingElements), overflow
+}
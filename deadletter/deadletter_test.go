@@ -0,0 +1,155 @@
+package deadletter_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	deadletter "github.com/antonio-alexander/go-queue/deadletter"
+	finite "github.com/antonio-alexander/go-queue/finite"
+	infinite "github.com/antonio-alexander/go-queue/infinite"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const casef string = "case: %s"
+
+var errFailed = errors.New("failed")
+
+func TestDeadLetterQueue(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		target := infinite.New(1)
+		defer target.Close()
+		q := deadletter.New(finite.New(10), target)
+		defer q.Close()
+		assert.False(t, q.Enqueue(1))
+		underflow, err := q.Attempt(func(item interface{}) error {
+			assert.Equal(t, 1, item)
+			return nil
+		})
+		assert.False(t, underflow)
+		assert.NoError(t, err)
+		underflow, err = q.Attempt(func(item interface{}) error {
+			assert.Fail(t, "unexpected attempt")
+			return nil
+		})
+		assert.True(t, underflow)
+		assert.NoError(t, err)
+		assert.Zero(t, target.Length())
+	})
+	t.Run("Max Attempts", func(t *testing.T) {
+		cases := map[string]struct {
+			iMaxAttempts int
+			iOptions     []deadletter.Option
+		}{
+			"default": {
+				iMaxAttempts: deadletter.DefaultMaxAttempts,
+			},
+			"one_attempt": {
+				iMaxAttempts: 1,
+				iOptions:     []deadletter.Option{deadletter.WithMaxAttempts(1)},
+			},
+			"five_attempts_in_front": {
+				iMaxAttempts: 5,
+				iOptions: []deadletter.Option{
+					deadletter.WithMaxAttempts(5),
+					deadletter.WithRequeueInFront(),
+				},
+			},
+		}
+		for cDesc, c := range cases {
+			target := infinite.New(1)
+			q := deadletter.New(infinite.New(10), target, c.iOptions...)
+			q.Enqueue(1)
+			start := time.Now()
+			for i := 0; i < c.iMaxAttempts; i++ {
+				assert.Zero(t, target.Length(), casef, cDesc)
+				underflow, err := q.Attempt(func(item interface{}) error {
+					return errFailed
+				})
+				assert.False(t, underflow, casef, cDesc)
+				assert.ErrorIs(t, err, errFailed, casef, cDesc)
+			}
+			_, underflow := q.Dequeue()
+			assert.True(t, underflow, casef, cDesc)
+			item, underflow := target.Dequeue()
+			assert.False(t, underflow, casef, cDesc)
+			deadLetter, ok := item.(*deadletter.DeadLetter)
+			if assert.True(t, ok, casef, cDesc) {
+				assert.Equal(t, 1, deadLetter.Item, casef, cDesc)
+				assert.Equal(t, c.iMaxAttempts, deadLetter.Failures, casef, cDesc)
+				assert.ErrorIs(t, deadLetter.LastError, errFailed, casef, cDesc)
+				assert.False(t, deadLetter.FirstAttempt.Before(start), casef, cDesc)
+				assert.False(t, deadLetter.LastAttempt.Before(deadLetter.FirstAttempt), casef, cDesc)
+			}
+			q.Close()
+			target.Close()
+		}
+	})
+	t.Run("Requeue Order", func(t *testing.T) {
+		//KIM: by default, failed items are put at the back of the queue
+		// so they don't block other items
+		target := infinite.New(1)
+		defer target.Close()
+		q := deadletter.New(finite.New(10), target)
+		q.EnqueueMultiple([]interface{}{1, 2, 3})
+		q.Attempt(func(item interface{}) error {
+			return errFailed
+		})
+		assert.Equal(t, []interface{}{2, 3, 1}, q.Close())
+
+		q = deadletter.New(finite.New(10), target, deadletter.WithRequeueInFront())
+		q.EnqueueMultiple([]interface{}{1, 2, 3})
+		q.Attempt(func(item interface{}) error {
+			return errFailed
+		})
+		assert.Equal(t, []interface{}{1, 2, 3}, q.Close())
+	})
+	t.Run("Dead Letter Overflow", func(t *testing.T) {
+		target := finite.New(1)
+		defer target.Close()
+		target.Enqueue(0)
+		q := deadletter.New(finite.New(1), target, deadletter.WithMaxAttempts(1))
+		defer q.Close()
+		q.Enqueue(1)
+		underflow, err := q.Attempt(func(item interface{}) error {
+			return errFailed
+		})
+		assert.False(t, underflow)
+		assert.ErrorIs(t, err, errFailed)
+		assert.Equal(t, 1, target.Length())
+		target.Flush()
+		_, err = q.Attempt(func(item interface{}) error {
+			return errFailed
+		})
+		assert.ErrorIs(t, err, errFailed)
+		item, _ := target.Dequeue()
+		deadLetter, ok := item.(*deadletter.DeadLetter)
+		if assert.True(t, ok) {
+			assert.Equal(t, 2, deadLetter.Failures)
+		}
+	})
+	t.Run("Requeue Overflow", func(t *testing.T) {
+		target := finite.New(1)
+		defer target.Close()
+		q := deadletter.New(finite.New(1), target, deadletter.WithMaxAttempts(3))
+		defer q.Close()
+		q.Enqueue(1)
+		//fill the queue while the item is being attempted such that it
+		// can't be put back
+		underflow, err := q.Attempt(func(item interface{}) error {
+			q.Enqueue(2)
+			return errFailed
+		})
+		assert.False(t, underflow)
+		assert.ErrorIs(t, err, deadletter.ErrRequeueOverflow)
+		item, _ := target.Dequeue()
+		deadLetter, ok := item.(*deadletter.DeadLetter)
+		if assert.True(t, ok) {
+			assert.Equal(t, 1, deadLetter.Item)
+			assert.Equal(t, 1, deadLetter.Failures)
+			assert.ErrorIs(t, deadLetter.LastError, errFailed)
+		}
+		assert.Equal(t, []interface{}{2}, q.Flush())
+	})
+}
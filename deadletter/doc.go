@@ -0,0 +1,10 @@
+// Copyright 2022 antonio-alexander. All rights reserved.
+// Use of this source code is governed by an MIT
+// license that can be found in the LICENSE file.
+
+/*
+	Package deadletter provides a wrapper for any queue that keeps track of
+	failed attempts to process an item and moves items that have failed too
+	many times into a separate (dead-letter) queue
+*/
+package deadletter
@@ -0,0 +1,75 @@
+package deadletter
+
+import (
+	"errors"
+	"time"
+)
+
+//DefaultMaxAttempts is the number of times an item can be attempted before
+// it's dead-lettered if no max attempts is configured
+const DefaultMaxAttempts = 3
+
+//ErrDeadLetterOverflow will be returned by Attempt() if an item should've
+// been dead-lettered, but both the dead-letter queue and the queue are
+// full; the item is discarded
+var ErrDeadLetterOverflow = errors.New("unable to dead-letter item: overflow")
+
+//ErrRequeueOverflow will be returned by Attempt() if an item failed fewer
+// than the max attempts, but couldn't be put back in the queue; the item is
+// dead-lettered early (its Failures will be less than the max attempts) and
+// the error returned by the function is available as its LastError
+var ErrRequeueOverflow = errors.New("unable to requeue item: overflow")
+
+//Metadata describes the attempts to process an item
+type Metadata struct {
+	Failures     int
+	LastError    error
+	FirstAttempt time.Time
+	LastAttempt  time.Time
+}
+
+//DeadLetter is what's enqueued into the dead-letter queue, it contains the
+// item that failed and the metadata describing its attempts
+type DeadLetter struct {
+	Item interface{}
+	Metadata
+}
+
+//Attempter can be used to dequeue an item and attempt to process it with
+// the given function, if the function returns an error, the failure is
+// recorded and the item is put back in the queue, once an item has failed
+// the max number of attempts, it's enqueued into the dead-letter queue as a
+// *DeadLetter. The error returned by the function is returned by Attempt()
+// unless the item couldn't be requeued (ErrRequeueOverflow) or dead-lettered
+// (ErrDeadLetterOverflow)
+type Attempter interface {
+	Attempt(fn func(item interface{}) error) (underflow bool, err error)
+}
+
+//Option can be used to configure a dead-letter queue on creation
+type Option func(*configuration)
+
+type configuration struct {
+	maxAttempts    int
+	requeueInFront bool
+}
+
+//WithMaxAttempts will configure the number of times an item can fail before
+// it's dead-lettered, if n is less than one, it will be one
+func WithMaxAttempts(n int) Option {
+	return func(c *configuration) {
+		if n < 1 {
+			n = 1
+		}
+		c.maxAttempts = n
+	}
+}
+
+//WithRequeueInFront will configure failed items to be put back at the front
+// of the queue (if the queue is an EnqueueInFronter) rather than the back
+// such that they're attempted again immediately
+func WithRequeueInFront() Option {
+	return func(c *configuration) {
+		c.requeueInFront = true
+	}
+}
@@ -0,0 +1,251 @@
+package delay
+
+import (
+	"sync"
+	"time"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+	internal "github.com/antonio-alexander/go-queue/internal"
+)
+
+type queueDelay[T any] struct {
+	sync.Mutex
+	signalIn  chan struct{}
+	signalOut chan struct{}
+	closed    bool
+	timer     *time.Timer
+	timerDue  time.Time
+	pending   schedule[T]
+	ready     ready[T]
+}
+
+//New can be used to create an un-bounded delay queue of empty interface,
+// items enqueued via Enqueue() or EnqueueMultiple() are due immediately
+func New() interface {
+	goqueue.Owner
+	goqueue.Dequeuer
+	goqueue.Enqueuer
+	goqueue.Length
+	goqueue.Event
+	goqueue.Peeker
+	EnqueueDelayed
+} {
+	return NewOf[interface{}]()
+}
+
+//NewOf can be used to create a type-safe un-bounded delay queue of T,
+// items enqueued via Enqueue() or EnqueueMultiple() are due immediately
+func NewOf[T any]() interface {
+	goqueue.OwnerOf[T]
+	goqueue.DequeuerOf[T]
+	goqueue.EnqueuerOf[T]
+	goqueue.Length
+	goqueue.Event
+	goqueue.PeekerOf[T]
+	EnqueueDelayedOf[T]
+} {
+	return &queueDelay[T]{
+		signalIn:  make(chan struct{}, 1),
+		signalOut: make(chan struct{}, 1),
+	}
+}
+
+//promote will move any items that are due from pending to ready (sending
+// a signal for each) and then re-arm the timer for the next item
+func (q *queueDelay[T]) promote(now time.Time) {
+	for {
+		due, ok := q.pending.next()
+		if !ok || due.After(now) {
+			break
+		}
+		q.ready.push(q.pending.pop())
+		internal.SendSignal(q.signalIn)
+	}
+	q.schedule(now)
+}
+
+//schedule will ensure that the timer is armed to fire when the next
+// pending item is due (or stopped if there are no pending items)
+func (q *queueDelay[T]) schedule(now time.Time) {
+	due, ok := q.pending.next()
+	switch {
+	case !ok:
+		if q.timer != nil {
+			q.timer.Stop()
+		}
+		q.timerDue = time.Time{}
+	case q.timerDue.IsZero() || !q.timerDue.Equal(due):
+		if q.timer == nil {
+			q.timer = time.AfterFunc(due.Sub(now), q.fire)
+		} else {
+			q.timer.Stop()
+			q.timer.Reset(due.Sub(now))
+		}
+		q.timerDue = due
+	}
+}
+
+//fire is executed by the timer when the next pending item is due
+func (q *queueDelay[T]) fire() {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.closed {
+		return
+	}
+	q.timerDue = time.Time{}
+	q.promote(time.Now())
+}
+
+func (q *queueDelay[T]) enqueue(item T, due time.Time) (overflow bool) {
+	if q.closed {
+		return true
+	}
+	now := time.Now()
+	q.promote(now)
+	if due.After(now) {
+		q.pending.push(item, due)
+		q.schedule(now)
+		return false
+	}
+	q.ready.push(item)
+	internal.SendSignal(q.signalIn)
+	return false
+}
+
+func (q *queueDelay[T]) dequeueMultiple(n int) (items []T) {
+	q.promote(time.Now())
+	if items = q.ready.popMultiple(n); len(items) > 0 {
+		internal.SendSignal(q.signalOut)
+	}
+	return
+}
+
+//Close will return all of the items in the queue, including those that
+// aren't due yet; items that are due are returned first
+func (q *queueDelay[T]) Close() (remainingElements []T) {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.closed {
+		return
+	}
+	if q.timer != nil {
+		q.timer.Stop()
+	}
+	remainingElements = append(q.ready.popMultiple(q.ready.size()), q.pending.items()...)
+	close(q.signalIn)
+	close(q.signalOut)
+	q.pending, q.ready = schedule[T]{}, ready[T]{}
+	q.timer, q.timerDue, q.closed = nil, time.Time{}, true
+
+	return
+}
+
+//GetSignalIn will return a channel that's signalled when an item becomes
+// due rather than when it's enqueued
+func (q *queueDelay[T]) GetSignalIn() (signal <-chan struct{}) {
+	q.Lock()
+	defer q.Unlock()
+	return q.signalIn
+}
+
+func (q *queueDelay[T]) GetSignalOut() (signal <-chan struct{}) {
+	q.Lock()
+	defer q.Unlock()
+	return q.signalOut
+}
+
+func (q *queueDelay[T]) Dequeue() (item T, underflow bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	items := q.dequeueMultiple(1)
+	if len(items) <= 0 {
+		return item, true
+	}
+	return items[0], false
+}
+
+func (q *queueDelay[T]) DequeueMultiple(n int) (items []T) {
+	q.Lock()
+	defer q.Unlock()
+	return q.dequeueMultiple(n)
+}
+
+//Flush will remove and return all of the items that are due
+func (q *queueDelay[T]) Flush() (items []T) {
+	q.Lock()
+	defer q.Unlock()
+
+	q.promote(time.Now())
+	return q.dequeueMultiple(q.ready.size())
+}
+
+func (q *queueDelay[T]) Enqueue(item T) (overflow bool) {
+	q.Lock()
+	defer q.Unlock()
+	return q.enqueue(item, time.Time{})
+}
+
+func (q *queueDelay[T]) EnqueueMultiple(items []T) (remainingElements []T, overflow bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	for i, item := range items {
+		if overflow = q.enqueue(item, time.Time{}); overflow {
+			return items[i:], true
+		}
+	}
+
+	return
+}
+
+func (q *queueDelay[T]) EnqueueAt(item T, due time.Time) (overflow bool) {
+	q.Lock()
+	defer q.Unlock()
+	return q.enqueue(item, due)
+}
+
+func (q *queueDelay[T]) EnqueueAfter(item T, d time.Duration) (overflow bool) {
+	q.Lock()
+	defer q.Unlock()
+	return q.enqueue(item, time.Now().Add(d))
+}
+
+//Length will return the number of items in the queue, including those
+// that aren't due yet
+func (q *queueDelay[T]) Length() (size int) {
+	q.Lock()
+	defer q.Unlock()
+	return q.ready.size() + q.pending.size()
+}
+
+//Peek will return all of the items that are due
+func (q *queueDelay[T]) Peek() (items []T) {
+	q.Lock()
+	defer q.Unlock()
+
+	q.promote(time.Now())
+	return q.ready.peek(q.ready.size())
+}
+
+func (q *queueDelay[T]) PeekHead() (item T, underflow bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	q.promote(time.Now())
+	items := q.ready.peek(1)
+	if len(items) <= 0 {
+		return item, true
+	}
+	return items[0], false
+}
+
+func (q *queueDelay[T]) PeekFromHead(n int) (items []T) {
+	q.Lock()
+	defer q.Unlock()
+
+	q.promote(time.Now())
+	return q.ready.peek(n)
+}
@@ -0,0 +1,213 @@
+package delay_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+	infinite_tests "github.com/antonio-alexander/go-queue/infinite/tests"
+	delay "github.com/antonio-alexander/go-queue/delay"
+	goqueue_tests "github.com/antonio-alexander/go-queue/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	mustTimeout = time.Second
+	mustRate    = time.Millisecond
+)
+
+func init() {
+	rand.Seed(int64(time.Now().Nanosecond()))
+}
+
+func TestQueue(t *testing.T) {
+	t.Run("Test Dequeue", goqueue_tests.TestDequeue(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return delay.New()
+	}))
+	t.Run("Test Dequeue Event", goqueue_tests.TestDequeueEvent(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Event
+	} {
+		return delay.New()
+	}))
+	t.Run("Test Dequeue Multiple", goqueue_tests.TestDequeueMultiple(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return delay.New()
+	}))
+	t.Run("Test Flush", goqueue_tests.TestFlush(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return delay.New()
+	}))
+	t.Run("Test Peek", goqueue_tests.TestPeek(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Peeker
+	} {
+		return delay.New()
+	}))
+	t.Run("Test Peek From Head", goqueue_tests.TestPeekFromHead(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Peeker
+	} {
+		return delay.New()
+	}))
+	t.Run("Test Event", goqueue_tests.TestEvent(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Event
+	} {
+		return delay.New()
+	}))
+	t.Run("Test Length", goqueue_tests.TestLength(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Length
+	} {
+		return delay.New()
+	}))
+	t.Run("Test Queue", goqueue_tests.TestQueue(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return delay.New()
+	}))
+	t.Run("Test Asynchronous", goqueue_tests.TestAsync(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return delay.New()
+	}))
+	t.Run("Test Queue Of", goqueue_tests.TestQueueOf(t, func(size int) interface {
+		goqueue.OwnerOf[*goqueue.Example]
+		goqueue.EnqueuerOf[*goqueue.Example]
+		goqueue.DequeuerOf[*goqueue.Example]
+		goqueue.PeekerOf[*goqueue.Example]
+	} {
+		return delay.NewOf[*goqueue.Example]()
+	}))
+	t.Run("Test Enqueue", infinite_tests.TestEnqueue(t, mustRate, mustTimeout, func() interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return delay.New()
+	}))
+	t.Run("Test Enqueue Multiple", infinite_tests.TestEnqueueMultiple(t, mustRate, mustTimeout, func() interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return delay.New()
+	}))
+	t.Run("Test Enqueue Event", infinite_tests.TestEnqueueEvent(t, mustRate, mustTimeout, func() interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Event
+	} {
+		return delay.New()
+	}))
+}
+
+func TestDelayQueue(t *testing.T) {
+	t.Run("Not Due", func(t *testing.T) {
+		q := delay.NewOf[int]()
+		defer q.Close()
+		assert.False(t, q.EnqueueAfter(1, time.Hour))
+		assert.False(t, q.EnqueueAt(2, time.Now().Add(time.Hour)))
+		assert.Equal(t, 2, q.Length())
+		_, underflow := q.PeekHead()
+		assert.True(t, underflow)
+		_, underflow = q.Dequeue()
+		assert.True(t, underflow)
+		assert.Empty(t, q.Peek())
+		assert.Empty(t, q.Flush())
+		select {
+		default:
+		case <-q.GetSignalIn():
+			assert.Fail(t, "unexpected signal in received")
+		}
+	})
+	t.Run("Due", func(t *testing.T) {
+		const wait = 50 * time.Millisecond
+
+		q := delay.NewOf[int]()
+		defer q.Close()
+		start := time.Now()
+		assert.False(t, q.EnqueueAfter(1, wait))
+		select {
+		case <-time.After(mustTimeout):
+			assert.Fail(t, "expected signal in not received")
+			return
+		case <-q.GetSignalIn():
+		}
+		assert.GreaterOrEqual(t, time.Since(start), wait)
+		item, underflow := q.PeekHead()
+		assert.False(t, underflow)
+		assert.Equal(t, 1, item)
+		item, underflow = q.Dequeue()
+		assert.False(t, underflow)
+		assert.Equal(t, 1, item)
+		assert.Zero(t, q.Length())
+	})
+	t.Run("Order", func(t *testing.T) {
+		q := delay.NewOf[int]()
+		defer q.Close()
+		now := time.Now()
+		q.EnqueueAt(3, now.Add(30*time.Millisecond))
+		q.EnqueueAt(1, now.Add(10*time.Millisecond))
+		q.EnqueueAt(4, now.Add(30*time.Millisecond))
+		q.EnqueueAt(2, now.Add(20*time.Millisecond))
+		q.EnqueueAt(0, now.Add(-time.Second))
+		item, underflow := q.Dequeue()
+		assert.False(t, underflow)
+		assert.Equal(t, 0, item)
+		time.Sleep(40 * time.Millisecond)
+		assert.Equal(t, []int{1, 2, 3, 4}, q.Peek())
+		assert.Equal(t, []int{1, 2, 3, 4}, q.Flush())
+	})
+	t.Run("Reschedule", func(t *testing.T) {
+		//KIM: this verifies that the timer is re-armed when an item is
+		// enqueued that's due before the item the timer was armed for
+		q := delay.NewOf[int]()
+		defer q.Close()
+		q.EnqueueAfter(2, time.Hour)
+		q.EnqueueAfter(1, 10*time.Millisecond)
+		select {
+		case <-time.After(mustTimeout):
+			assert.Fail(t, "expected signal in not received")
+		case <-q.GetSignalIn():
+		}
+		assert.Equal(t, []int{1}, q.Flush())
+		assert.Equal(t, 1, q.Length())
+	})
+	t.Run("Close", func(t *testing.T) {
+		q := delay.NewOf[int]()
+		q.EnqueueAfter(2, time.Hour)
+		q.Enqueue(1)
+		assert.Equal(t, []int{1, 2}, q.Close())
+		assert.True(t, q.EnqueueAfter(3, time.Millisecond))
+		assert.True(t, q.Enqueue(3))
+		assert.Nil(t, q.Close())
+	})
+}
@@ -0,0 +1,9 @@
+// Copyright 2022 antonio-alexander. All rights reserved.
+// Use of this source code is governed by an MIT
+// license that can be found in the LICENSE file.
+
+/*
+	Package delay provides an un-bounded queue implementation where items
+	are scheduled and can't be dequeued (or peeked) until they're due
+*/
+package delay
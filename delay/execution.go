@@ -0,0 +1,146 @@
+package delay
+
+import "time"
+
+type element[T any] struct {
+	item     T
+	due      time.Time
+	sequence uint64
+}
+
+//schedule is a binary min-heap of elements ordered by when they're due
+// and then by sequence such that elements that are due at the same time
+// are popped in the order they were pushed
+type schedule[T any] struct {
+	data     []element[T]
+	sequence uint64
+}
+
+func (s *schedule[T]) less(i, j int) bool {
+	if !s.data[i].due.Equal(s.data[j].due) {
+		return s.data[i].due.Before(s.data[j].due)
+	}
+	return s.data[i].sequence < s.data[j].sequence
+}
+
+func (s *schedule[T]) swap(i, j int) {
+	s.data[i], s.data[j] = s.data[j], s.data[i]
+}
+
+func (s *schedule[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !s.less(i, parent) {
+			return
+		}
+		s.swap(i, parent)
+		i = parent
+	}
+}
+
+func (s *schedule[T]) down(i int) {
+	for {
+		left, right, first := 2*i+1, 2*i+2, i
+		if left < len(s.data) && s.less(left, first) {
+			first = left
+		}
+		if right < len(s.data) && s.less(right, first) {
+			first = right
+		}
+		if first == i {
+			return
+		}
+		s.swap(i, first)
+		i = first
+	}
+}
+
+func (s *schedule[T]) size() int {
+	return len(s.data)
+}
+
+func (s *schedule[T]) push(item T, due time.Time) {
+	s.data = append(s.data, element[T]{
+		item:     item,
+		due:      due,
+		sequence: s.sequence,
+	})
+	s.sequence++
+	s.up(len(s.data) - 1)
+}
+
+//next will return when the earliest element is due, ok will be false
+// if the schedule is empty
+func (s *schedule[T]) next() (due time.Time, ok bool) {
+	if len(s.data) <= 0 {
+		return
+	}
+	return s.data[0].due, true
+}
+
+func (s *schedule[T]) pop() (item T) {
+	var empty element[T]
+
+	last := len(s.data) - 1
+	item = s.data[0].item
+	s.swap(0, last)
+	s.data[last] = empty
+	s.data = s.data[:last]
+	s.down(0)
+	return
+}
+
+//items will return all of the items in the order they're due
+func (s *schedule[T]) items() (items []T) {
+	c := &schedule[T]{data: make([]element[T], len(s.data))}
+	copy(c.data, s.data)
+	for c.size() > 0 {
+		items = append(items, c.pop())
+	}
+	return
+}
+
+//ready is a simple fifo of items that are due
+type ready[T any] struct {
+	data []T
+}
+
+func (r *ready[T]) size() int {
+	return len(r.data)
+}
+
+func (r *ready[T]) push(item T) {
+	r.data = append(r.data, item)
+}
+
+func (r *ready[T]) popMultiple(n int) (items []T) {
+	var empty T
+
+	if n > len(r.data) {
+		n = len(r.data)
+	}
+	if n <= 0 {
+		return
+	}
+	items = make([]T, n)
+	copy(items, r.data[:n])
+	for i := 0; i < n; i++ {
+		r.data[i] = empty
+	}
+	if r.data = r.data[n:]; len(r.data) == 0 {
+		r.data = nil
+	}
+	return
+}
+
+func (r *ready[T]) peek(n int) (items []T) {
+	if n > len(r.data) {
+		n = len(r.data)
+	}
+	if n <= 0 {
+		return
+	}
+	items = make([]T, n)
+	copy(items, r.data[:n])
+	return
+}
@@ -0,0 +1,20 @@
+package delay
+
+import "time"
+
+//EnqueueDelayedOf can be used to schedule an item of T such that it can't be
+// dequeued until it's due
+type EnqueueDelayedOf[T any] interface {
+	//EnqueueAt will enqueue an item that will be due at the given time, if
+	// the time is in the past, it will be due immediately
+	EnqueueAt(item T, due time.Time) (overflow bool)
+
+	//EnqueueAfter will enqueue an item that will be due after the given
+	// duration, if the duration isn't greater than zero, it will be due
+	// immediately
+	EnqueueAfter(item T, d time.Duration) (overflow bool)
+}
+
+//EnqueueDelayed can be used to schedule an item such that it can't be
+// dequeued until it's due
+type EnqueueDelayed = EnqueueDelayedOf[interface{}]
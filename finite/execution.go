@@ -0,0 +1,214 @@
+package finite
+
+//ring is a fixed size circular buffer, head is the index of the oldest
+// item (the front of the queue) and size is the number of items; the
+// back of the queue is always (head+size)%capacity so no items ever need
+// to be moved to enqueue or dequeue from either end
+type ring[T any] struct {
+	data []T
+	head int
+	size int
+}
+
+//newRing can be used to create a ring with the given capacity
+func newRing[T any](capacity int) ring[T] {
+	return ring[T]{data: make([]T, capacity)}
+}
+
+//capacity returns the maximum number of items the ring can hold
+func (r *ring[T]) capacity() int {
+	return len(r.data)
+}
+
+//full returns true if the ring can't hold any more items
+func (r *ring[T]) full() bool {
+	return r.size >= len(r.data)
+}
+
+//index converts the position of an item relative to the head into the
+// index of the item within data
+func (r *ring[T]) index(i int) int {
+	return (r.head + i) % len(r.data)
+}
+
+//at returns the item at position i relative to the head
+func (r *ring[T]) at(i int) T {
+	return r.data[r.index(i)]
+}
+
+//pushBack can be used to add an item to the back of the ring, it will
+// return true if the ring is full
+func (r *ring[T]) pushBack(item T) (overflow bool) {
+	if r.full() {
+		return true
+	}
+	r.data[r.index(r.size)] = item
+	r.size++
+	return false
+}
+
+//pushFront can be used to add an item to the front of the ring, it will
+// return true if the ring is full
+func (r *ring[T]) pushFront(item T) (overflow bool) {
+	if r.full() {
+		return true
+	}
+	r.head = (r.head + len(r.data) - 1) % len(r.data)
+	r.data[r.head] = item
+	r.size++
+	return false
+}
+
+//popFront can be used to remove the item at the front of the ring, the
+// slot is zeroed so the item can be garbage collected, it will return
+// true if the ring is empty
+func (r *ring[T]) popFront() (item T, underflow bool) {
+	var zero T
+
+	if r.size <= 0 {
+		return zero, true
+	}
+	item, r.data[r.head] = r.data[r.head], zero
+	r.head = (r.head + 1) % len(r.data)
+	r.size--
+	return item, false
+}
+
+//popFrontMultiple can be used to remove up to n items from the front of
+// the ring, it will return true if the ring is empty
+func (r *ring[T]) popFrontMultiple(n int) (items []T, underflow bool) {
+	if r.size <= 0 {
+		return nil, true
+	}
+	if n > r.size {
+		n = r.size
+	}
+	if n < 0 {
+		n = 0
+	}
+	items = make([]T, 0, n)
+	for i := 0; i < n; i++ {
+		item, _ := r.popFront()
+		items = append(items, item)
+	}
+	return items, false
+}
+
+//popBack can be used to remove the item at the back of the ring, the
+// slot is zeroed so the item can be garbage collected, it will return
+// true if the ring is empty
+func (r *ring[T]) popBack() (item T, underflow bool) {
+	var zero T
+
+	if r.size <= 0 {
+		return zero, true
+	}
+	r.size--
+	item, r.data[r.index(r.size)] = r.at(r.size), zero
+	return item, false
+}
+
+//popBackMultiple can be used to remove up to n items from the back of
+// the ring, the items are returned in the order they're removed (the
+// last item first), it will return true if the ring is empty
+func (r *ring[T]) popBackMultiple(n int) (items []T, underflow bool) {
+	if r.size <= 0 {
+		return nil, true
+	}
+	if n > r.size {
+		n = r.size
+	}
+	if n < 0 {
+		n = 0
+	}
+	items = make([]T, 0, n)
+	for i := 0; i < n; i++ {
+		item, _ := r.popBack()
+		items = append(items, item)
+	}
+	return items, false
+}
+
+//removeAt can be used to remove the item at position i relative to the
+// head, the items behind it are moved forward to fill the gap; it expects
+// that i is less than the size
+func (r *ring[T]) removeAt(i int) (item T) {
+	var zero T
+
+	item = r.at(i)
+	for ; i < r.size-1; i++ {
+		r.data[r.index(i)] = r.at(i + 1)
+	}
+	r.data[r.index(r.size-1)] = zero
+	r.size--
+	return item
+}
+
+//removeIf can be used to remove every item the predicate is true for, the
+// remaining items are moved forward (keeping their order) to fill the gaps
+func (r *ring[T]) removeIf(pred func(item T) bool) (items []T) {
+	var zero T
+
+	j := 0
+	for i := 0; i < r.size; i++ {
+		item := r.at(i)
+		if pred(item) {
+			items = append(items, item)
+			continue
+		}
+		r.data[r.index(j)] = item
+		j++
+	}
+	for i := j; i < r.size; i++ {
+		r.data[r.index(i)] = zero
+	}
+	r.size = j
+	return items
+}
+
+//find returns the first item (and its position relative to the head)
+// the predicate is true for
+func (r *ring[T]) find(pred func(item T) bool) (index int, item T, ok bool) {
+	for i := 0; i < r.size; i++ {
+		if item := r.at(i); pred(item) {
+			return i, item, true
+		}
+	}
+	return -1, item, false
+}
+
+//peekFront can be used to copy up to n items from the front of the ring
+// without removing them
+func (r *ring[T]) peekFront(n int) (items []T) {
+	if n > r.size {
+		n = r.size
+	}
+	for i := 0; i < n; i++ {
+		items = append(items, r.at(i))
+	}
+	return items
+}
+
+//peekBack can be used to copy up to n items from the back of the ring
+// without removing them, the last item first
+func (r *ring[T]) peekBack(n int) (items []T) {
+	if n > r.size {
+		n = r.size
+	}
+	for i := 0; i < n; i++ {
+		items = append(items, r.at(r.size-1-i))
+	}
+	return items
+}
+
+//resize will return a new ring with the given capacity that contains
+// the items of the current ring, starting at the head, it expects that
+// the new capacity is greater than or equal to the current size
+func (r *ring[T]) resize(capacity int) ring[T] {
+	newRing := newRing[T](capacity)
+	for i := 0; i < r.size; i++ {
+		newRing.data[i] = r.at(i)
+	}
+	newRing.size = r.size
+	return newRing
+}
@@ -1,48 +1,205 @@
 package finite
 
 import (
+	"context"
+	"math/rand"
 	"sync"
 
 	goqueue "github.com/antonio-alexander/go-queue"
 	internal "github.com/antonio-alexander/go-queue/internal"
 )
 
-type queueFinite struct {
+type queueFinite[T any] struct {
 	sync.RWMutex
 	signalIn  chan struct{}
 	signalOut chan struct{}
-	data      []interface{}
+	changed   internal.Notifier
+	closed    bool
+	data      ring[T]
+	stats     *internal.Stats
+	configuration
 }
 
-func New(size int) interface {
+//New can be used to create a finite queue of empty interface with
+// the given size, if size is less than one, it will be one; the options
+// can be used to configure what happens when the queue is full
+func New(size int, options ...Option) interface {
 	goqueue.Owner
 	goqueue.GarbageCollecter
 	goqueue.Dequeuer
+	goqueue.DequeuerCtx
 	goqueue.Enqueuer
+	goqueue.EnqueuerCtx
 	goqueue.EnqueueInFronter
+	goqueue.DequeueFromBacker
+	goqueue.ConditionalDequeuer
 	goqueue.Length
 	goqueue.Event
 	goqueue.Peeker
+	goqueue.PeekerCtx
+	goqueue.PeekFromTailer
+	goqueue.Remover
+	goqueue.Statser
 	EnqueueLossy
 	Resizer
 	Capacity
+} {
+	return NewOf[interface{}](size, options...)
+}
+
+//NewOf can be used to create a type-safe finite queue of T with the
+// given size, if size is less than one, it will be one
+func NewOf[T any](size int, options ...Option) interface {
+	goqueue.OwnerOf[T]
+	goqueue.GarbageCollecter
+	goqueue.DequeuerOf[T]
+	goqueue.DequeuerCtxOf[T]
+	goqueue.EnqueuerOf[T]
+	goqueue.EnqueuerCtxOf[T]
+	goqueue.EnqueueInFronterOf[T]
+	goqueue.DequeueFromBackerOf[T]
+	goqueue.ConditionalDequeuerOf[T]
+	goqueue.Length
+	goqueue.Event
+	goqueue.PeekerOf[T]
+	goqueue.PeekerCtxOf[T]
+	goqueue.PeekFromTailerOf[T]
+	goqueue.RemoverOf[T]
+	goqueue.Statser
+	EnqueueLossyOf[T]
+	ResizerOf[T]
+	Capacity
 } {
 	maxSize := size
 	if maxSize < 1 {
 		maxSize = 1
 	}
-	return &queueFinite{
+	q := &queueFinite[T]{
 		signalIn:  make(chan struct{}, maxSize),
 		signalOut: make(chan struct{}, maxSize),
-		data:      make([]interface{}, 0, maxSize),
+		data:      newRing[T](maxSize),
+		stats:     new(internal.Stats),
+	}
+	for _, option := range options {
+		option(&q.configuration)
+	}
+	return q
+}
+
+//wait will block until the state of the queue changes, the context is
+// done or the queue is closed; it expects the queue to be locked and will
+// return with the queue locked
+func (q *queueFinite[T]) wait(ctx context.Context) error {
+	if q.closed {
+		return goqueue.ErrQueueClosed
+	}
+	changed := q.changed.Wait()
+	q.Unlock()
+	defer q.Lock()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-changed:
+		return nil
 	}
 }
 
-func (q *queueFinite) Close() (remainingElements []interface{}) {
+//discard will provide the discarded item to the drop func (if configured),
+// it expects the queue to be locked
+func (q *queueFinite[T]) discard(item T) {
+	q.stats.LossyDiscard()
+	if q.dropFunc != nil {
+		q.dropFunc(item)
+	}
+}
+
+//makeRoom will apply the overflow policy if the queue is full, room will
+// be true if the item can be enqueued and overflow will be true if the
+// item was rejected (as opposed to discarded); it expects the queue to be
+// locked
+func (q *queueFinite[T]) makeRoom(item T) (room, overflow bool) {
+	if q.closed {
+		return false, true
+	}
+	if !q.data.full() {
+		return true, false
+	}
+	switch q.overflowPolicy {
+	default:
+		return false, true
+	case OverflowDropNewest:
+		q.discard(item)
+		return false, false
+	case OverflowDropOldest:
+		discardedElement, _ := q.data.popFront()
+		q.discard(discardedElement)
+	case OverflowDropRandom:
+		q.discard(q.data.removeAt(rand.Intn(q.data.size)))
+	case OverflowBlock:
+		ctx := context.Background()
+		if q.blockTimeout > 0 {
+			var cancel context.CancelFunc
+
+			ctx, cancel = context.WithTimeout(ctx, q.blockTimeout)
+			defer cancel()
+		}
+		for q.data.full() {
+			if err := q.wait(ctx); err != nil {
+				return false, true
+			}
+		}
+	}
+	return true, false
+}
+
+func (q *queueFinite[T]) dequeue() (item T, underflow bool) {
+	if item, underflow = q.data.popFront(); !underflow {
+		q.stats.Dequeued(1)
+		q.stats.SendSignal(q.signalOut)
+		q.changed.Notify()
+	}
+	return
+}
+
+func (q *queueFinite[T]) dequeueMultiple(n int) (items []T) {
+	var underflow bool
+
+	if items, underflow = q.data.popFrontMultiple(n); !underflow {
+		q.stats.Dequeued(len(items))
+		q.stats.SendSignal(q.signalOut)
+		q.changed.Notify()
+	}
+	return
+}
+
+func (q *queueFinite[T]) enqueue(item T) (overflow bool) {
+	if overflow = q.data.pushBack(item); !overflow {
+		q.stats.Enqueued(1, q.data.size)
+		q.stats.SendSignal(q.signalIn)
+		q.changed.Notify()
+	}
+	return
+}
+
+func (q *queueFinite[T]) enqueueMultiple(items []T) (remainingElements []T, overflow bool) {
+	for i, item := range items {
+		if overflow = q.enqueue(item); overflow {
+			remainingElements = items[i:]
+			return
+		}
+	}
+	return
+}
+
+func (q *queueFinite[T]) peekFromHead(n int) (items []T) {
+	return q.data.peekFront(n)
+}
+
+func (q *queueFinite[T]) Close() (remainingElements []T) {
 	q.Lock()
 	defer q.Unlock()
 
-	remainingElements, q.data, _ = internal.DequeueMultiple(cap(q.data), q.data)
+	remainingElements, _ = q.data.popFrontMultiple(q.data.size)
 	if q.signalIn != nil {
 		select {
 		default:
@@ -57,41 +214,41 @@ func (q *queueFinite) Close() (remainingElements []interface{}) {
 		case <-q.signalOut:
 		}
 	}
-	q.data, q.signalIn, q.signalOut = nil, nil, nil
+	q.data, q.signalIn, q.signalOut = ring[T]{}, nil, nil
+	q.closed = true
+	q.changed.Notify()
 
 	return
 }
 
-func (q *queueFinite) GarbageCollect() {
+func (q *queueFinite[T]) GarbageCollect() {
 	q.Lock()
 	defer q.Unlock()
 
-	//create a new slice to hold the data copy the data
-	// from the old slice to the new slice and set the
-	// internal data to be the new slice
-	data := make([]interface{}, 0, cap(q.data))
-	copy(data, q.data)
-	q.data = data
+	//create a new ring to hold the data copy the data
+	// from the old ring to the new ring and set the
+	// internal data to be the new ring
+	q.data = q.data.resize(q.data.capacity())
+	q.stats.GarbageCollect()
 }
 
-func (q *queueFinite) Resize(newSize int) (items []interface{}) {
+func (q *queueFinite[T]) Resize(newSize int) (items []T) {
 	q.Lock()
 	defer q.Unlock()
 
 	//ensure that no operations occur if the size hasn't changed,
 	// if there's a need to remove items, remove them, then copy the old
 	// data to the newly created slice, create new signal channels
-	if newSize == cap(q.data) {
+	if newSize == q.data.capacity() {
 		return
 	}
 	if newSize < 1 {
 		newSize = 1
 	}
-	if len(q.data) > newSize {
-		items, q.data, _ = internal.DequeueMultiple(len(q.data)-newSize, q.data)
+	if q.data.size > newSize {
+		items, _ = q.data.popFrontMultiple(q.data.size - newSize)
 	}
-	data := make([]interface{}, len(q.data), newSize)
-	copy(data, q.data[:len(q.data)])
+	data := q.data.resize(newSize)
 	if q.signalIn != nil {
 		select {
 		default:
@@ -109,159 +266,383 @@ func (q *queueFinite) Resize(newSize int) (items []interface{}) {
 	q.data = data
 	q.signalIn = make(chan struct{}, newSize)
 	q.signalOut = make(chan struct{}, newSize)
+	q.stats.Resize()
+	q.changed.Notify()
 
 	return
 }
 
-func (q *queueFinite) GetSignalIn() (signal <-chan struct{}) {
+func (q *queueFinite[T]) GetSignalIn() (signal <-chan struct{}) {
 	q.RLock()
 	defer q.RUnlock()
 	return q.signalIn
 }
 
-func (q *queueFinite) GetSignalOut() (signal <-chan struct{}) {
+func (q *queueFinite[T]) GetSignalOut() (signal <-chan struct{}) {
 	q.RLock()
 	defer q.RUnlock()
 	return q.signalOut
 }
 
-func (q *queueFinite) Dequeue() (item interface{}, underflow bool) {
+func (q *queueFinite[T]) Dequeue() (item T, underflow bool) {
 	q.Lock()
 	defer q.Unlock()
 
-	if item, q.data, underflow = internal.Dequeue(q.data); !underflow {
-		internal.SendSignal(q.signalOut)
+	if item, underflow = q.dequeue(); underflow {
+		q.stats.Underflow()
 	}
 
 	return
 }
 
-func (q *queueFinite) DequeueMultiple(n int) (items []interface{}) {
+func (q *queueFinite[T]) DequeueCtx(ctx context.Context) (item T, err error) {
 	q.Lock()
 	defer q.Unlock()
 
-	var underflow bool
+	for {
+		var underflow bool
+
+		if item, underflow = q.dequeue(); !underflow {
+			return
+		}
+		if err = q.wait(ctx); err != nil {
+			return
+		}
+	}
+}
+
+func (q *queueFinite[T]) DequeueMultiple(n int) (items []T) {
+	q.Lock()
+	defer q.Unlock()
 
-	if items, q.data, underflow = internal.DequeueMultiple(n, q.data); !underflow {
-		internal.SendSignal(q.signalOut)
+	if items = q.dequeueMultiple(n); len(items) <= 0 {
+		q.stats.Underflow()
 	}
 
 	return
 }
 
-func (q *queueFinite) Flush() (items []interface{}) {
+func (q *queueFinite[T]) DequeueMultipleCtx(ctx context.Context, n int) (items []T, err error) {
 	q.Lock()
 	defer q.Unlock()
 
-	var underflow bool
+	for len(items) < n {
+		items = append(items, q.dequeueMultiple(n-len(items))...)
+		if len(items) >= n {
+			break
+		}
+		if err = q.wait(ctx); err != nil {
+			return
+		}
+	}
 
-	if len(q.data) <= 0 {
+	return
+}
+
+func (q *queueFinite[T]) Flush() (items []T) {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.data.size <= 0 {
+		q.stats.Underflow()
 		return
 	}
-	if items, q.data, underflow = internal.DequeueMultiple(cap(q.data), q.data); !underflow {
-		internal.SendSignal(q.signalOut)
+	return q.dequeueMultiple(q.data.size)
+}
+
+func (q *queueFinite[T]) Enqueue(item T) (overflow bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	var room bool
+
+	if room, overflow = q.makeRoom(item); room {
+		overflow = q.enqueue(item)
+	}
+	if overflow {
+		q.stats.Overflow()
 	}
 
 	return
 }
 
-func (q *queueFinite) Enqueue(item interface{}) (overflow bool) {
+func (q *queueFinite[T]) EnqueueCtx(ctx context.Context, item T) (err error) {
 	q.Lock()
 	defer q.Unlock()
 
-	if overflow, q.data = internal.Enqueue(q.data, item); !overflow {
-		internal.SendSignal(q.signalIn)
+	for {
+		if q.closed {
+			return goqueue.ErrQueueClosed
+		}
+		if overflow := q.enqueue(item); !overflow {
+			return
+		}
+		if err = q.wait(ctx); err != nil {
+			return
+		}
 	}
-
-	return
 }
 
-func (q *queueFinite) EnqueueMultiple(items []interface{}) (remainingElements []interface{}, overflow bool) {
+func (q *queueFinite[T]) EnqueueMultiple(items []T) (remainingElements []T, overflow bool) {
 	q.Lock()
 	defer q.Unlock()
 
 	for i, item := range items {
-		if overflow, q.data = internal.Enqueue(q.data, item); overflow {
+		var room bool
+
+		if room, overflow = q.makeRoom(item); room {
+			overflow = q.enqueue(item)
+		}
+		if overflow {
 			remainingElements = items[i:]
-			internal.SendSignal(q.signalIn)
+			q.stats.Overflow()
+			return
+		}
+	}
+
+	return
+}
+
+func (q *queueFinite[T]) EnqueueMultipleCtx(ctx context.Context, items []T) (remainingElements []T, err error) {
+	q.Lock()
+	defer q.Unlock()
+
+	remainingElements = items
+	for {
+		var overflow bool
 
+		if q.closed {
+			return remainingElements, goqueue.ErrQueueClosed
+		}
+		if remainingElements, overflow = q.enqueueMultiple(remainingElements); !overflow {
 			return
 		}
+		if err = q.wait(ctx); err != nil {
+			return
+		}
+	}
+}
+
+func (q *queueFinite[T]) EnqueueLossy(item T) (discardedElement T, discard bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	//KIM: once closed, the capacity is zero so the queue is always full,
+	// the item can't be enqueued and nothing is actually discarded
+	if q.closed {
+		q.stats.Overflow()
+		return item, true
+	}
+	if q.data.full() {
+		var underflow bool
+
+		if discardedElement, underflow = q.data.popFront(); !underflow {
+			discard = true
+			q.discard(discardedElement)
+		}
+	}
+	if overflow := q.data.pushBack(item); overflow {
+		q.stats.Overflow()
+		return item, true
 	}
+	q.stats.Enqueued(1, q.data.size)
+	q.stats.SendSignal(q.signalIn)
+	q.changed.Notify()
 
 	return
 }
 
-func (q *queueFinite) EnqueueLossy(item interface{}) (discardedElement interface{}, discard bool) {
+func (q *queueFinite[T]) EnqueueInFront(item T) (overflow bool) {
 	q.Lock()
 	defer q.Unlock()
 
-	if len(q.data) >= cap(q.data) {
-		discard = true
-		discardedElement, q.data, _ = internal.Dequeue(q.data)
+	var room bool
+
+	if room, overflow = q.makeRoom(item); !room {
+		if overflow {
+			q.stats.Overflow()
+		}
+		return
 	}
-	_, q.data = internal.Enqueue(q.data, item)
-	internal.SendSignal(q.signalIn)
+	if overflow = q.data.pushFront(item); overflow {
+		q.stats.Overflow()
+		return
+	}
+	q.stats.Enqueued(1, q.data.size)
+	q.stats.SendSignal(q.signalIn)
+	q.changed.Notify()
 
 	return
 }
 
-func (q *queueFinite) EnqueueInFront(item interface{}) (overflow bool) {
+func (q *queueFinite[T]) DequeueBack() (item T, underflow bool) {
 	q.Lock()
 	defer q.Unlock()
 
-	if overflow, q.data = internal.EnqueueInFront(q.data, item); !overflow {
-		internal.SendSignal(q.signalIn)
+	if item, underflow = q.data.popBack(); underflow {
+		q.stats.Underflow()
+		return
 	}
+	q.stats.Dequeued(1)
+	q.stats.SendSignal(q.signalOut)
+	q.changed.Notify()
 
 	return
 }
 
-func (q *queueFinite) Length() (size int) {
+func (q *queueFinite[T]) DequeueMultipleBack(n int) (items []T) {
+	q.Lock()
+	defer q.Unlock()
+
+	var underflow bool
+
+	if items, underflow = q.data.popBackMultiple(n); underflow {
+		q.stats.Underflow()
+		return
+	}
+	q.stats.Dequeued(len(items))
+	q.stats.SendSignal(q.signalOut)
+	q.changed.Notify()
+
+	return
+}
+
+func (q *queueFinite[T]) DequeueIf(pred func(item T) bool) (item T, underflow bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.data.size <= 0 {
+		q.stats.Underflow()
+		return item, true
+	}
+	if !pred(q.data.at(0)) {
+		return item, true
+	}
+
+	return q.dequeue()
+}
+
+func (q *queueFinite[T]) DequeueWhile(pred func(item T) bool, max int) (items []T) {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.data.size <= 0 {
+		q.stats.Underflow()
+		return
+	}
+	for len(items) < max && q.data.size > 0 && pred(q.data.at(0)) {
+		item, _ := q.data.popFront()
+		items = append(items, item)
+	}
+	if len(items) > 0 {
+		q.stats.Dequeued(len(items))
+		q.stats.SendSignal(q.signalOut)
+		q.changed.Notify()
+	}
+
+	return
+}
+
+func (q *queueFinite[T]) RemoveIf(pred func(item T) bool) (items []T) {
+	q.Lock()
+	defer q.Unlock()
+
+	if items = q.data.removeIf(pred); len(items) > 0 {
+		q.stats.Dequeued(len(items))
+		q.stats.SendSignal(q.signalOut)
+		q.changed.Notify()
+	}
+
+	return
+}
+
+func (q *queueFinite[T]) Find(pred func(item T) bool) (index int, item T, ok bool) {
 	q.RLock()
 	defer q.RUnlock()
-	return len(q.data)
+	return q.data.find(pred)
 }
 
-func (q *queueFinite) Capacity() (capacity int) {
+func (q *queueFinite[T]) Contains(pred func(item T) bool) (ok bool) {
 	q.RLock()
 	defer q.RUnlock()
-	return cap(q.data)
+	_, _, ok = q.data.find(pred)
+	return
 }
 
-func (q *queueFinite) Peek() (items []interface{}) {
+func (q *queueFinite[T]) Length() (size int) {
 	q.RLock()
 	defer q.RUnlock()
+	return q.data.size
+}
 
-	for i := 0; i < len(q.data); i++ {
-		items = append(items, q.data[i])
-	}
+func (q *queueFinite[T]) Capacity() (capacity int) {
+	q.RLock()
+	defer q.RUnlock()
+	return q.data.capacity()
+}
 
-	return
+func (q *queueFinite[T]) Peek() (items []T) {
+	q.RLock()
+	defer q.RUnlock()
+	return q.data.peekFront(q.data.size)
 }
 
-func (q *queueFinite) PeekHead() (item interface{}, underflow bool) {
+func (q *queueFinite[T]) PeekHead() (item T, underflow bool) {
 	q.RLock()
 	defer q.RUnlock()
-	if len(q.data) <= 0 {
-		return nil, true
+	if q.data.size <= 0 {
+		return item, true
+	}
+	return q.data.at(0), false
+}
+
+func (q *queueFinite[T]) PeekHeadCtx(ctx context.Context) (item T, err error) {
+	q.Lock()
+	defer q.Unlock()
+
+	for q.data.size <= 0 {
+		if err = q.wait(ctx); err != nil {
+			return
+		}
 	}
-	return q.data[0], false
+	return q.data.at(0), nil
 }
 
-func (q *queueFinite) PeekFromHead(n int) (items []interface{}) {
+func (q *queueFinite[T]) PeekFromHead(n int) (items []T) {
 	q.RLock()
 	defer q.RUnlock()
+	return q.peekFromHead(n)
+}
 
-	if len(q.data) == 0 {
-		return
-	}
-	if n > len(q.data) {
-		n = len(q.data)
+func (q *queueFinite[T]) PeekFromHeadCtx(ctx context.Context, n int) (items []T, err error) {
+	q.Lock()
+	defer q.Unlock()
+
+	//KIM: if n is greater than the capacity, we can only wait
+	// until the queue is full
+	for q.data.size < n && !q.data.full() {
+		if err = q.wait(ctx); err != nil {
+			return q.peekFromHead(n), err
+		}
 	}
-	for i := 0; i < n; i++ {
-		items = append(items, q.data[i])
+	return q.peekFromHead(n), nil
+}
+
+func (q *queueFinite[T]) PeekTail() (item T, underflow bool) {
+	q.RLock()
+	defer q.RUnlock()
+	if q.data.size <= 0 {
+		return item, true
 	}
+	return q.data.at(q.data.size - 1), false
+}
 
-	return
+func (q *queueFinite[T]) PeekFromTail(n int) (items []T) {
+	q.RLock()
+	defer q.RUnlock()
+	return q.data.peekBack(n)
+}
+
+func (q *queueFinite[T]) Stats() (stats goqueue.Stats) {
+	return q.stats.Snapshot()
 }
@@ -9,11 +9,14 @@ import (
 	finite "github.com/antonio-alexander/go-queue/finite"
 	finite_tests "github.com/antonio-alexander/go-queue/finite/tests"
 	goqueue_tests "github.com/antonio-alexander/go-queue/tests"
+
+	"github.com/stretchr/testify/assert"
 )
 
 const (
 	mustTimeout = time.Second
 	mustRate    = time.Millisecond
+	casef       = "case: %s"
 )
 
 func init() {
@@ -65,6 +68,13 @@ func TestFiniteQueue(t *testing.T) {
 	} {
 		return finite.New(size)
 	}))
+	t.Run("Test Enqueue Ctx", finite_tests.TestEnqueueCtx(t, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Dequeuer
+		goqueue.EnqueuerCtx
+	} {
+		return finite.New(size)
+	}))
 	t.Run("Test Capacity", finite_tests.TestCapacity(t, func(size int) interface {
 		goqueue.Owner
 		goqueue.Enqueuer
@@ -73,6 +83,16 @@ func TestFiniteQueue(t *testing.T) {
 	} {
 		return finite.New(size)
 	}))
+	t.Run("Test Stats", finite_tests.TestStats(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.EnqueueInFronter
+		goqueue.Statser
+		finite.EnqueueLossy
+		finite.Resizer
+	} {
+		return finite.New(size)
+	}))
 }
 
 func TestQueue(t *testing.T) {
@@ -121,6 +141,63 @@ func TestQueue(t *testing.T) {
 	} {
 		return finite.New(size)
 	}))
+	t.Run("Test Dequeue Back", goqueue_tests.TestDequeueBack(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.DequeueFromBacker
+	} {
+		return finite.New(size)
+	}))
+	t.Run("Test Peek From Tail", goqueue_tests.TestPeekFromTail(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.PeekFromTailer
+	} {
+		return finite.New(size)
+	}))
+	t.Run("Test Remover", goqueue_tests.TestRemover(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Remover
+		goqueue.Statser
+	} {
+		return finite.New(size)
+	}))
+	t.Run("Test Conditional Dequeue", goqueue_tests.TestConditionalDequeue(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.ConditionalDequeuer
+	} {
+		return finite.New(size)
+	}))
+	t.Run("Test Conditional Dequeue Asynchronous", goqueue_tests.TestConditionalDequeueAsync(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Peeker
+		goqueue.ConditionalDequeuer
+	} {
+		return finite.New(size)
+	}))
+	t.Run("Test Dequeue Ctx", goqueue_tests.TestDequeueCtx(t, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.DequeuerCtx
+	} {
+		return finite.New(size)
+	}))
+	t.Run("Test Peek Ctx", goqueue_tests.TestPeekCtx(t, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Length
+		goqueue.PeekerCtx
+	} {
+		return finite.New(size)
+	}))
 	t.Run("Test Length", goqueue_tests.TestLength(t, func(size int) interface {
 		goqueue.Owner
 		goqueue.Enqueuer
@@ -152,4 +229,236 @@ func TestQueue(t *testing.T) {
 	} {
 		return finite.New(size)
 	}))
+	t.Run("Test Stats", goqueue_tests.TestStats(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Statser
+	} {
+		return finite.New(size)
+	}))
 }
+
+func TestFiniteQueueOf(t *testing.T) {
+	t.Run("Test Queue Of", goqueue_tests.TestQueueOf(t, func(size int) interface {
+		goqueue.OwnerOf[*goqueue.Example]
+		goqueue.EnqueuerOf[*goqueue.Example]
+		goqueue.DequeuerOf[*goqueue.Example]
+		goqueue.PeekerOf[*goqueue.Example]
+	} {
+		return finite.NewOf[*goqueue.Example](size)
+	}))
+	t.Run("Test Enqueue Lossy Of", finite_tests.TestEnqueueLossyOf(t, func(size int) interface {
+		goqueue.OwnerOf[*goqueue.Example]
+		goqueue.PeekerOf[*goqueue.Example]
+		finite.EnqueueLossyOf[*goqueue.Example]
+	} {
+		return finite.NewOf[*goqueue.Example](size)
+	}))
+	//KIM: a queue of empty interface satisfies the non-generic interfaces
+	// so the existing test suites can be used as-is
+	t.Run("Test Queue", goqueue_tests.TestQueue(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return finite.NewOf[interface{}](size)
+	}))
+	t.Run("Test Enqueue Lossy", finite_tests.TestEnqueueLossy(t, func(size int) interface {
+		goqueue.Owner
+		finite.EnqueueLossy
+	} {
+		return finite.NewOf[interface{}](size)
+	}))
+}
+
+func TestOverflowPolicy(t *testing.T) {
+	t.Run("Test Full", func(t *testing.T) {
+		cases := map[string]struct {
+			iPolicy    finite.OverflowPolicy
+			oOverflow  bool
+			oItems     []interface{}
+			oDiscarded []interface{}
+		}{
+			"reject": {
+				iPolicy:   finite.OverflowReject,
+				oOverflow: true,
+				oItems:    []interface{}{0, 1, 2},
+			},
+			"drop oldest": {
+				iPolicy:    finite.OverflowDropOldest,
+				oItems:     []interface{}{1, 2, 3},
+				oDiscarded: []interface{}{0},
+			},
+			"drop newest": {
+				iPolicy:    finite.OverflowDropNewest,
+				oItems:     []interface{}{0, 1, 2},
+				oDiscarded: []interface{}{3},
+			},
+		}
+		for cDesc, c := range cases {
+			var discarded []interface{}
+
+			//fill the queue, then enqueue another item and confirm that
+			// the policy is applied and the discarded items are provided
+			// to the drop func
+			q := finite.New(3, finite.WithOverflowPolicy(c.iPolicy),
+				finite.WithDropFunc(func(item interface{}) {
+					discarded = append(discarded, item)
+				}))
+			for i := 0; i < 3; i++ {
+				assert.False(t, q.Enqueue(i), casef, cDesc)
+			}
+			assert.Equal(t, c.oOverflow, q.Enqueue(3), casef, cDesc)
+			assert.Equal(t, c.oItems, q.Peek(), casef, cDesc)
+			assert.Equal(t, c.oDiscarded, discarded, casef, cDesc)
+			assert.Equal(t, c.oItems, q.Close(), casef, cDesc)
+
+			//confirm that enqueuing into a closed queue overflows no
+			// matter the policy
+			assert.True(t, q.Enqueue(4), casef, cDesc)
+			assert.Equal(t, c.oDiscarded, discarded, casef, cDesc)
+		}
+	})
+	t.Run("Test Drop Random", func(t *testing.T) {
+		const size = 5
+
+		var discarded []interface{}
+
+		//fill the queue, then enqueue more items and confirm that the
+		// newest item is always enqueued and each discarded item is no
+		// longer in the queue
+		q := finite.New(size, finite.WithOverflowPolicy(finite.OverflowDropRandom),
+			finite.WithDropFunc(func(item interface{}) {
+				discarded = append(discarded, item)
+			}))
+		defer q.Close()
+		remaining, overflow := q.EnqueueMultiple([]interface{}{0, 1, 2, 3, 4, 5, 6})
+		assert.False(t, overflow)
+		assert.Empty(t, remaining)
+		assert.Len(t, discarded, 2)
+		assert.Equal(t, 6, q.Peek()[size-1])
+		assert.False(t, q.EnqueueInFront(7))
+		assert.Len(t, discarded, 3)
+		items := q.Peek()
+		assert.Len(t, items, size)
+		assert.Equal(t, 7, items[0])
+		for _, item := range discarded {
+			assert.NotContains(t, items, item)
+		}
+		assert.Equal(t, 3, int(q.Stats().LossyDiscards))
+	})
+	t.Run("Test Block", func(t *testing.T) {
+		const timeout = 10 * time.Millisecond
+
+		//fill the queue, then enqueue another item and confirm that it
+		// overflows once the timeout has passed
+		q := finite.New(1, finite.WithOverflowPolicy(finite.OverflowBlock),
+			finite.WithBlockTimeout(timeout))
+		defer q.Close()
+		assert.False(t, q.Enqueue(0))
+		tStart := time.Now()
+		assert.True(t, q.Enqueue(1))
+		assert.GreaterOrEqual(t, time.Since(tStart), timeout)
+
+		//dequeue while the enqueue is blocked and confirm that the item
+		// is enqueued once there's room
+		go func() {
+			time.Sleep(timeout / 2)
+			q.Dequeue()
+		}()
+		assert.False(t, q.Enqueue(1))
+		assert.Equal(t, []interface{}{1}, q.Peek())
+
+		//close the queue while the enqueue is blocked (without a timeout)
+		// and confirm that it overflows
+		q = finite.New(1, finite.WithOverflowPolicy(finite.OverflowBlock))
+		assert.False(t, q.Enqueue(0))
+		go func() {
+			time.Sleep(timeout)
+			q.Close()
+		}()
+		assert.True(t, q.Enqueue(1))
+	})
+	t.Run("Test Lossy Closed", func(t *testing.T) {
+		var discarded []interface{}
+
+		//close the queue and confirm that a lossy enqueue returns the item
+		// without calling the drop func or counting it as enqueued
+		q := finite.New(1, finite.WithDropFunc(func(item interface{}) {
+			discarded = append(discarded, item)
+		}))
+		assert.False(t, q.Enqueue(0))
+		assert.Equal(t, []interface{}{0}, q.Close())
+		item, discard := q.EnqueueLossy(1)
+		assert.True(t, discard)
+		assert.Equal(t, 1, item)
+		assert.Empty(t, discarded)
+		stats := q.Stats()
+		assert.Equal(t, 1, int(stats.Enqueued))
+		assert.Zero(t, stats.LossyDiscards)
+		assert.Equal(t, 1, int(stats.Overflow))
+	})
+}
+
+func benchmarkDequeue(b *testing.B, size int) {
+	q := finite.New(size)
+	defer q.Close()
+	for i := 0; i < size; i++ {
+		q.Enqueue(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		item, _ := q.Dequeue()
+		q.Enqueue(item)
+	}
+}
+
+func benchmarkDequeueMultiple(b *testing.B, size int) {
+	const n = 16
+
+	q := finite.New(size)
+	defer q.Close()
+	for i := 0; i < size; i++ {
+		q.Enqueue(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		items := q.DequeueMultiple(n)
+		q.EnqueueMultiple(items)
+	}
+}
+
+func benchmarkEnqueueLossy(b *testing.B, size int) {
+	q := finite.New(size)
+	defer q.Close()
+	for i := 0; i < size; i++ {
+		q.Enqueue(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.EnqueueLossy(i)
+	}
+}
+
+func benchmarkEnqueueInFront(b *testing.B, size int) {
+	q := finite.New(size)
+	defer q.Close()
+	for i := 0; i < size-1; i++ {
+		q.Enqueue(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.EnqueueInFront(i)
+		q.Dequeue()
+	}
+}
+
+func BenchmarkDequeue100(b *testing.B)           { benchmarkDequeue(b, 100) }
+func BenchmarkDequeue10000(b *testing.B)         { benchmarkDequeue(b, 10000) }
+func BenchmarkDequeueMultiple100(b *testing.B)   { benchmarkDequeueMultiple(b, 100) }
+func BenchmarkDequeueMultiple10000(b *testing.B) { benchmarkDequeueMultiple(b, 10000) }
+func BenchmarkEnqueueLossy100(b *testing.B)      { benchmarkEnqueueLossy(b, 100) }
+func BenchmarkEnqueueLossy10000(b *testing.B)    { benchmarkEnqueueLossy(b, 10000) }
+func BenchmarkEnqueueInFront100(b *testing.B)    { benchmarkEnqueueInFront(b, 100) }
+func BenchmarkEnqueueInFront10000(b *testing.B)  { benchmarkEnqueueInFront(b, 10000) }
@@ -426,3 +426,165 @@ func TestCapacity(t *testing.T, newQueue func(int) interface {
 		}
 	}
 }
+
+// TestEnqueueLossyOf can be used to verify the type-safe version of EnqueueLossy(), it
+// confirms that the discarded element is returned as the type rather than as empty interface
+// and that the zero value is returned when nothing is discarded
+func TestEnqueueLossyOf(t *testing.T, newQueue func(int) interface {
+	goqueue.OwnerOf[*goqueue.Example]
+	goqueue.PeekerOf[*goqueue.Example]
+	finite.EnqueueLossyOf[*goqueue.Example]
+}) func(*testing.T) {
+	return func(t *testing.T) {
+		cases := map[string]struct {
+			iSize            int
+			iExamples        []*goqueue.Example
+			oDiscardExamples []*goqueue.Example
+			oExamples        []*goqueue.Example
+		}{
+			"normal_enqueue": {
+				iSize:            3,
+				iExamples:        []*goqueue.Example{{Int: 1}, {Int: 2}, {Int: 3}},
+				oDiscardExamples: []*goqueue.Example{nil, nil, nil},
+				oExamples:        []*goqueue.Example{{Int: 1}, {Int: 2}, {Int: 3}},
+			},
+			"lossy_enqueue": {
+				iSize:            3,
+				iExamples:        []*goqueue.Example{{Int: 1}, {Int: 2}, {Int: 3}, {Int: 4}, {Int: 5}},
+				oDiscardExamples: []*goqueue.Example{nil, nil, nil, {Int: 1}, {Int: 2}},
+				oExamples:        []*goqueue.Example{{Int: 3}, {Int: 4}, {Int: 5}},
+			},
+		}
+		for cDesc, c := range cases {
+			q := newQueue(c.iSize)
+			for i, example := range c.iExamples {
+				discardedExample, discard := q.EnqueueLossy(example)
+				assert.Equal(t, c.oDiscardExamples[i] != nil, discard, casef, cDesc)
+				assert.Equal(t, c.oDiscardExamples[i], discardedExample, casef, cDesc)
+			}
+			assert.Equal(t, c.oExamples, q.Peek(), casef, cDesc)
+			assert.Equal(t, c.oExamples, q.Close(), casef, cDesc)
+		}
+	}
+}
+
+// TestEnqueueCtx can be used to verify the context-aware enqueue functions for finite
+// queues, it will confirm that EnqueueCtx() blocks while the queue is full until an item
+// is dequeued, that ctx.Err() is returned if the context is done and ErrQueueClosed is
+// returned if the queue is closed while waiting
+func TestEnqueueCtx(t *testing.T, timeout time.Duration, newQueue func(size int) interface {
+	goqueue.Owner
+	goqueue.Dequeuer
+	goqueue.EnqueuerCtx
+}) func(*testing.T) {
+	return func(t *testing.T) {
+		const wait = 10 * time.Millisecond
+
+		//generate size and examples
+		size := 1 + int(10*rand.Float64())
+		examples := goqueue.ExampleGenFloat64(size)
+		items := make([]interface{}, 0, len(examples))
+		for _, example := range examples {
+			items = append(items, example)
+		}
+
+		//create queue
+		q := newQueue(size)
+		defer q.Close()
+
+		//fill the queue
+		ctx, cancel := context.WithTimeout(context.TODO(), timeout)
+		defer cancel()
+		itemsRemaining, err := q.EnqueueMultipleCtx(ctx, items)
+		cancel()
+		assert.Nil(t, err)
+		assert.Empty(t, itemsRemaining)
+
+		//attempt to enqueue (confirm timeout)
+		example := &goqueue.Example{Int: rand.Int()}
+		ctx, cancel = context.WithTimeout(context.TODO(), wait)
+		defer cancel()
+		err = q.EnqueueCtx(ctx, example)
+		cancel()
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		//dequeue while waiting and confirm that enqueue wakes up
+		go func() {
+			<-time.After(wait)
+			_, underflow := q.Dequeue()
+			assert.False(t, underflow)
+		}()
+		ctx, cancel = context.WithTimeout(context.TODO(), timeout)
+		defer cancel()
+		err = q.EnqueueCtx(ctx, example)
+		cancel()
+		assert.Nil(t, err)
+
+		//attempt to enqueue multiple (confirm items remaining)
+		ctx, cancel = context.WithTimeout(context.TODO(), wait)
+		defer cancel()
+		itemsRemaining, err = q.EnqueueMultipleCtx(ctx, items)
+		cancel()
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, items, itemsRemaining)
+
+		//close the queue while waiting and confirm that enqueue returns
+		// the closed error
+		go func() {
+			<-time.After(wait)
+			q.Close()
+		}()
+		ctx, cancel = context.WithTimeout(context.TODO(), timeout)
+		defer cancel()
+		err = q.EnqueueCtx(ctx, example)
+		cancel()
+		assert.ErrorIs(t, err, goqueue.ErrQueueClosed)
+	}
+}
+
+// TestStats can be used to verify the counters returned by Stats() that are specific
+// to finite queues, it will confirm that overflow from Enqueue(), EnqueueMultiple() and
+// EnqueueInFront() is counted, that items discarded by EnqueueLossy() are counted and
+// that Resize() is counted
+func TestStats(t *testing.T, newQueue func(size int) interface {
+	goqueue.Owner
+	goqueue.Enqueuer
+	goqueue.EnqueueInFronter
+	goqueue.Statser
+	finite.EnqueueLossy
+	finite.Resizer
+}) func(*testing.T) {
+	return func(t *testing.T) {
+		//create a queue and fill it
+		q := newQueue(2)
+		defer q.Close()
+		assert.False(t, q.Enqueue(&goqueue.Example{Int: 1}))
+		assert.False(t, q.Enqueue(&goqueue.Example{Int: 2}))
+
+		//attempt to enqueue into a full queue and confirm that each
+		// overflow is counted
+		assert.True(t, q.Enqueue(&goqueue.Example{Int: 3}))
+		_, overflow := q.EnqueueMultiple([]interface{}{&goqueue.Example{Int: 3}})
+		assert.True(t, overflow)
+		assert.True(t, q.EnqueueInFront(&goqueue.Example{Int: 3}))
+		stats := q.Stats()
+		assert.Equal(t, uint64(3), stats.Overflow)
+		assert.Equal(t, uint64(2), stats.Enqueued)
+		assert.Equal(t, uint64(2), stats.HighWaterMark)
+
+		//enqueue lossy and confirm that the discard is counted
+		_, discard := q.EnqueueLossy(&goqueue.Example{Int: 3})
+		assert.True(t, discard)
+		stats = q.Stats()
+		assert.Equal(t, uint64(1), stats.LossyDiscards)
+		assert.Equal(t, uint64(3), stats.Enqueued)
+
+		//resize the queue and confirm that it's counted (but only if
+		// the size changes)
+		q.Resize(2)
+		assert.Zero(t, q.Stats().Resizes)
+		q.Resize(4)
+		assert.Equal(t, uint64(1), q.Stats().Resizes)
+	}
+}
+
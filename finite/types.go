@@ -1,16 +1,25 @@
 package finite
 
+import "time"
+
 //Resizer can be used to modify the size of the queue, it will return any elements
 // that can't fit in the new queue. Keep in mind that this is destructive and will
 // invalidate and signal channels that have been created
-type Resizer interface {
-	Resize(size int) (items []interface{})
+type Resizer = ResizerOf[interface{}]
+
+//ResizerOf is the type-safe version of Resizer
+type ResizerOf[T any] interface {
+	Resize(size int) (items []T)
 }
 
 //EnqueueLossy can be used to add an element to the back of the queue, if
-// the queue is full, the oldest element will be discarded and returned
-type EnqueueLossy interface {
-	EnqueueLossy(item interface{}) (discardedElement interface{}, discard bool)
+// the queue is full, the oldest element will be discarded and returned; if
+// the queue is closed, the element can't be enqueued and is returned
+type EnqueueLossy = EnqueueLossyOf[interface{}]
+
+//EnqueueLossyOf is the type-safe version of EnqueueLossy
+type EnqueueLossyOf[T any] interface {
+	EnqueueLossy(item T) (discardedElement T, discard bool)
 }
 
 //Capacity can be used to determine the maximum size of a given
@@ -18,3 +27,64 @@ type EnqueueLossy interface {
 type Capacity interface {
 	Capacity() (capacity int)
 }
+
+//OverflowPolicy describes what a finite queue does when an item is enqueued
+// (via Enqueue(), EnqueueMultiple() or EnqueueInFront()) while it's full
+type OverflowPolicy int
+
+const (
+	//OverflowReject will reject the item, the enqueue will overflow
+	OverflowReject OverflowPolicy = iota
+	//OverflowDropOldest will discard the item at the front of the queue
+	// to make room for the item
+	OverflowDropOldest
+	//OverflowDropNewest will discard the item being enqueued, the enqueue
+	// won't overflow
+	OverflowDropNewest
+	//OverflowDropRandom will discard a random item in the queue to make
+	// room for the item
+	OverflowDropRandom
+	//OverflowBlock will wait until there's room for the item, the queue
+	// is closed or the block timeout has passed; the enqueue will overflow
+	// if there isn't room
+	OverflowBlock
+)
+
+//DropFunc is called with each item discarded by the queue because of its
+// overflow policy (or EnqueueLossy()), it's called while the queue is
+// locked so it must not use the queue
+type DropFunc func(item interface{})
+
+//Option can be used to configure a finite queue on creation
+type Option func(*configuration)
+
+type configuration struct {
+	overflowPolicy OverflowPolicy
+	blockTimeout   time.Duration
+	dropFunc       DropFunc
+}
+
+//WithOverflowPolicy will configure what the queue does when an item is
+// enqueued while it's full, the default is OverflowReject
+func WithOverflowPolicy(policy OverflowPolicy) Option {
+	return func(c *configuration) {
+		c.overflowPolicy = policy
+	}
+}
+
+//WithBlockTimeout will configure how long an enqueue will wait for room
+// if the overflow policy is OverflowBlock, if the timeout isn't greater
+// than zero, it will wait until there's room or the queue is closed
+func WithBlockTimeout(timeout time.Duration) Option {
+	return func(c *configuration) {
+		c.blockTimeout = timeout
+	}
+}
+
+//WithDropFunc will configure a function that's called with each item
+// discarded by the queue
+func WithDropFunc(dropFunc DropFunc) Option {
+	return func(c *configuration) {
+		c.dropFunc = dropFunc
+	}
+}
@@ -1,30 +1,272 @@
 package infinite
 
-import (
-	internal "github.com/antonio-alexander/go-queue/internal"
-)
+//chunk is a fixed size segment of an infinite queue, chunks are doubly
+// linked so that items can be added or removed from either end without
+// having to copy any of the other items
+type chunk[T any] struct {
+	data []T
+	prev *chunk[T]
+	next *chunk[T]
+}
+
+//chunks is a linked list of fixed size chunks; head is the index of the
+// first item within the first chunk and tail is the index after the last
+// item within the last chunk. When the head moves past the end of a chunk
+// (or the tail moves past the start of one) the chunk is released so that
+// memory shrinks as the queue shrinks, a single spare chunk is kept to
+// avoid allocating when a queue hovers around a chunk boundary
+type chunks[T any] struct {
+	first     *chunk[T]
+	last      *chunk[T]
+	spare     *chunk[T]
+	head      int
+	tail      int
+	size      int
+	chunkSize int
+}
+
+//newChunks can be used to create an empty list of chunks where each
+// chunk will hold chunkSize items
+func newChunks[T any](chunkSize int) chunks[T] {
+	return chunks[T]{chunkSize: chunkSize}
+}
+
+//allocate will return the spare chunk if available, otherwise it
+// will create a new chunk and grew will be true
+func (c *chunks[T]) allocate() (allocated *chunk[T], grew bool) {
+	if spare := c.spare; spare != nil {
+		c.spare = nil
+		return spare, false
+	}
+	return &chunk[T]{data: make([]T, c.chunkSize)}, true
+}
+
+//release will unlink the given chunk and keep it as the spare if there
+// isn't one, it expects that the items in the chunk have been zeroed
+func (c *chunks[T]) release(released *chunk[T]) {
+	released.prev, released.next = nil, nil
+	if c.spare == nil {
+		c.spare = released
+	}
+}
+
+//pushBack can be used to add an item to the back of the list, a new
+// chunk will be appended if the last chunk is full, grew will be true
+// if a new chunk had to be created
+func (c *chunks[T]) pushBack(item T) (grew bool) {
+	switch {
+	case c.last == nil:
+		c.first, grew = c.allocate()
+		c.last = c.first
+		c.head, c.tail = 0, 0
+	case c.tail >= c.chunkSize:
+		var last *chunk[T]
+
+		last, grew = c.allocate()
+		last.prev, c.last.next = c.last, last
+		c.last, c.tail = last, 0
+	}
+	c.last.data[c.tail] = item
+	c.tail++
+	c.size++
+	return
+}
+
+//pushFront can be used to add an item to the front of the list, a new
+// chunk will be prepended if there's no room in front of the head, grew
+// will be true if a new chunk had to be created
+func (c *chunks[T]) pushFront(item T) (grew bool) {
+	switch {
+	case c.first == nil:
+		c.first, grew = c.allocate()
+		c.last = c.first
+		c.head, c.tail = c.chunkSize, c.chunkSize
+	case c.head <= 0:
+		var first *chunk[T]
 
-//growIfFull can be used to increase the size of the slice by the
-// growSize if the length of the slice is greater than or equal
-// to the capacity of the slice
-func growIfFull(growSize int, data []interface{}) []interface{} {
-	if len(data) < cap(data) {
-		return data
+		first, grew = c.allocate()
+		first.next, c.first.prev = c.first, first
+		c.first, c.head = first, c.chunkSize
+	}
+	c.head--
+	c.first.data[c.head] = item
+	c.size++
+	return
+}
+
+//popFront can be used to remove the item at the front of the list, it
+// will return true if the list is empty
+func (c *chunks[T]) popFront() (item T, underflow bool) {
+	var zero T
+
+	if c.size <= 0 {
+		return zero, true
+	}
+	item, c.first.data[c.head] = c.first.data[c.head], zero
+	c.head++
+	c.size--
+	switch {
+	case c.size == 0:
+		c.release(c.first)
+		c.first, c.last = nil, nil
+	case c.head >= c.chunkSize:
+		first := c.first
+		c.first, c.first.prev = first.next, nil
+		c.head = 0
+		c.release(first)
 	}
-	return append(make([]interface{}, 0, cap(data)+growSize), data...)
+	return item, false
 }
 
-//enqueue can be used to add an item to the back of the slice, if the slice is
-// full, it's grown by the growSize, and then the item is appended to the slice
-func enqueue(data []interface{}, item interface{}, growSize int) []interface{} {
-	data = growIfFull(growSize, data)
-	return append(data, item)
+//popFrontMultiple can be used to remove up to n items from the front of
+// the list, it will return true if the list is empty
+func (c *chunks[T]) popFrontMultiple(n int) (items []T, underflow bool) {
+	if c.size <= 0 {
+		return nil, true
+	}
+	if n > c.size {
+		n = c.size
+	}
+	if n < 0 {
+		n = 0
+	}
+	items = make([]T, 0, n)
+	for i := 0; i < n; i++ {
+		item, _ := c.popFront()
+		items = append(items, item)
+	}
+	return items, false
 }
 
-//enqueueInFront can be used to add an item to the front of the slice, if the slice is
-// full, it's grown by the growSize, and then the item is added.
-func enqueueInFront(data []interface{}, item interface{}, growSize int) []interface{} {
-	data = growIfFull(growSize, data)
-	data = append(data, item)
-	return internal.RotateRight(data)
+//popBack can be used to remove the item at the back of the list, it
+// will return true if the list is empty
+func (c *chunks[T]) popBack() (item T, underflow bool) {
+	var zero T
+
+	if c.size <= 0 {
+		return zero, true
+	}
+	c.tail--
+	item, c.last.data[c.tail] = c.last.data[c.tail], zero
+	c.size--
+	switch {
+	case c.size == 0:
+		c.release(c.last)
+		c.first, c.last = nil, nil
+	case c.tail <= 0:
+		last := c.last
+		c.last, c.last.next = last.prev, nil
+		c.tail = c.chunkSize
+		c.release(last)
+	}
+	return item, false
+}
+
+//popBackMultiple can be used to remove up to n items from the back of
+// the list, the items are returned in the order they're removed (the
+// last item first), it will return true if the list is empty
+func (c *chunks[T]) popBackMultiple(n int) (items []T, underflow bool) {
+	if c.size <= 0 {
+		return nil, true
+	}
+	if n > c.size {
+		n = c.size
+	}
+	if n < 0 {
+		n = 0
+	}
+	items = make([]T, 0, n)
+	for i := 0; i < n; i++ {
+		item, _ := c.popBack()
+		items = append(items, item)
+	}
+	return items, false
+}
+
+//removeIf can be used to remove every item the predicate is true for, the
+// remaining items are moved forward (keeping their order) to fill the gaps
+// and the chunks at the back that are no longer needed are released
+func (c *chunks[T]) removeIf(pred func(item T) bool) (items []T) {
+	written, w := c.first, c.head
+	for current, i, index := c.first, 0, c.head; i < c.size; i, index = i+1, index+1 {
+		if index >= c.chunkSize {
+			current, index = current.next, 0
+		}
+		item := current.data[index]
+		if pred(item) {
+			items = append(items, item)
+			continue
+		}
+		if w >= c.chunkSize {
+			written, w = written.next, 0
+		}
+		written.data[w] = item
+		w++
+	}
+	for range items {
+		c.popBack()
+	}
+	return items
+}
+
+//find returns the first item (and its position relative to the front)
+// the predicate is true for
+func (c *chunks[T]) find(pred func(item T) bool) (index int, item T, ok bool) {
+	for current, i, index := c.first, 0, c.head; i < c.size; i, index = i+1, index+1 {
+		if index >= c.chunkSize {
+			current, index = current.next, 0
+		}
+		if item := current.data[index]; pred(item) {
+			return i, item, true
+		}
+	}
+	return -1, item, false
+}
+
+//peekFront can be used to copy up to n items from the front of the
+// list without removing them
+func (c *chunks[T]) peekFront(n int) (items []T) {
+	if n > c.size {
+		n = c.size
+	}
+	for current, i, index := c.first, 0, c.head; i < n; i, index = i+1, index+1 {
+		if index >= c.chunkSize {
+			current, index = current.next, 0
+		}
+		items = append(items, current.data[index])
+	}
+	return items
+}
+
+//front returns the item at the front of the list, it will return
+// true if the list is empty
+func (c *chunks[T]) front() (item T, underflow bool) {
+	if c.size <= 0 {
+		return item, true
+	}
+	return c.first.data[c.head], false
+}
+
+//peekBack can be used to copy up to n items from the back of the list
+// without removing them, the last item first
+func (c *chunks[T]) peekBack(n int) (items []T) {
+	if n > c.size {
+		n = c.size
+	}
+	for current, i, index := c.last, 0, c.tail-1; i < n; i, index = i+1, index-1 {
+		if index < 0 {
+			current, index = current.prev, c.chunkSize-1
+		}
+		items = append(items, current.data[index])
+	}
+	return items
+}
+
+//back returns the item at the back of the list, it will return
+// true if the list is empty
+func (c *chunks[T]) back() (item T, underflow bool) {
+	if c.size <= 0 {
+		return item, true
+	}
+	return c.last.data[c.tail-1], false
 }
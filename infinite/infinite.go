@@ -1,47 +1,127 @@
 package infinite
 
 import (
-	"math"
+	"context"
 	"sync"
 
 	goqueue "github.com/antonio-alexander/go-queue"
 	internal "github.com/antonio-alexander/go-queue/internal"
 )
 
-type queueInfinite struct {
+type queueInfinite[T any] struct {
 	sync.RWMutex
-	growSize  int
 	signalIn  chan struct{}
 	signalOut chan struct{}
-	data      []interface{}
+	changed   internal.Notifier
+	closed    bool
+	data      chunks[T]
+	stats     *internal.Stats
 }
 
+//New can be used to create an infinite queue of empty interface that
+// will grow by growSize when full, if growSize is less than one, it
+// will be one. The queue is made up of chunks of growSize items that
+// are released as the queue shrinks
 func New(growSize int) interface {
 	goqueue.Owner
 	goqueue.GarbageCollecter
 	goqueue.Dequeuer
+	goqueue.DequeuerCtx
 	goqueue.Enqueuer
+	goqueue.EnqueuerCtx
 	goqueue.EnqueueInFronter
+	goqueue.DequeueFromBacker
+	goqueue.ConditionalDequeuer
 	goqueue.Length
 	goqueue.Event
 	goqueue.Peeker
+	goqueue.PeekerCtx
+	goqueue.PeekFromTailer
+	goqueue.Remover
+	goqueue.Statser
+} {
+	return NewOf[interface{}](growSize)
+}
+
+//NewOf can be used to create a type-safe infinite queue of T that will
+// grow by growSize when full, if growSize is less than one, it will be
+// one
+func NewOf[T any](growSize int) interface {
+	goqueue.OwnerOf[T]
+	goqueue.GarbageCollecter
+	goqueue.DequeuerOf[T]
+	goqueue.DequeuerCtxOf[T]
+	goqueue.EnqueuerOf[T]
+	goqueue.EnqueuerCtxOf[T]
+	goqueue.EnqueueInFronterOf[T]
+	goqueue.DequeueFromBackerOf[T]
+	goqueue.ConditionalDequeuerOf[T]
+	goqueue.Length
+	goqueue.Event
+	goqueue.PeekerOf[T]
+	goqueue.PeekerCtxOf[T]
+	goqueue.PeekFromTailerOf[T]
+	goqueue.RemoverOf[T]
+	goqueue.Statser
 } {
 	if growSize < 1 {
 		growSize = 1
 	}
-	return &queueInfinite{
-		growSize:  growSize,
-		data:      make([]interface{}, 0, growSize),
+	return &queueInfinite[T]{
+		data:      newChunks[T](growSize),
 		signalIn:  make(chan struct{}),
 		signalOut: make(chan struct{}),
+		stats:     new(internal.Stats),
+	}
+}
+
+//wait will block until the state of the queue changes, the context is
+// done or the queue is closed; it expects the queue to be locked and will
+// return with the queue locked
+func (q *queueInfinite[T]) wait(ctx context.Context) error {
+	if q.closed {
+		return goqueue.ErrQueueClosed
+	}
+	changed := q.changed.Wait()
+	q.Unlock()
+	defer q.Lock()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-changed:
+		return nil
+	}
+}
+
+func (q *queueInfinite[T]) dequeueMultiple(n int) (items []T) {
+	var underflow bool
+
+	if items, underflow = q.data.popFrontMultiple(n); !underflow {
+		q.stats.Dequeued(len(items))
+		q.stats.SendSignal(q.signalOut, ConfigSignalTimeout)
+		q.changed.Notify()
+	}
+	return
+}
+
+func (q *queueInfinite[T]) enqueue(item T) {
+	if grew := q.data.pushBack(item); grew {
+		q.stats.Resize()
 	}
+	q.stats.Enqueued(1, q.data.size)
+	q.stats.SendSignal(q.signalIn, ConfigSignalTimeout)
+	q.changed.Notify()
+}
+
+func (q *queueInfinite[T]) peekFromHead(n int) (items []T) {
+	return q.data.peekFront(n)
 }
 
-func (q *queueInfinite) Close() (remainingElements []interface{}) {
+func (q *queueInfinite[T]) Close() (remainingElements []T) {
 	q.Lock()
 	defer q.Unlock()
 
-	remainingElements, q.data, _ = internal.DequeueMultiple(cap(q.data), q.data)
+	remainingElements, _ = q.data.popFrontMultiple(q.data.size)
 	if q.signalIn != nil {
 		select {
 		default:
@@ -56,150 +136,337 @@ func (q *queueInfinite) Close() (remainingElements []interface{}) {
 		case <-q.signalOut:
 		}
 	}
-	q.data, q.signalIn, q.signalOut = nil, nil, nil
-	q.growSize = 0
+	q.data = newChunks[T](q.data.chunkSize)
+	q.signalIn, q.signalOut = nil, nil
+	q.closed = true
+	q.changed.Notify()
+	return
+}
+
+func (q *queueInfinite[T]) GarbageCollect() {
+	q.Lock()
+	defer q.Unlock()
+
+	//chunks are released as the head moves past them, so the only
+	// memory left to collect is the spare chunk
+	q.data.spare = nil
+	q.stats.GarbageCollect()
+}
+
+func (q *queueInfinite[T]) Dequeue() (item T, underflow bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	item, underflow = q.data.popFront()
+	q.stats.SendSignal(q.signalOut, ConfigSignalTimeout)
+	if underflow {
+		q.stats.Underflow()
+		return
+	}
+	q.stats.Dequeued(1)
+	q.changed.Notify()
+
 	return
 }
 
-func (q *queueInfinite) GarbageCollect() {
+func (q *queueInfinite[T]) DequeueCtx(ctx context.Context) (item T, err error) {
 	q.Lock()
 	defer q.Unlock()
 
-	var length, newSize, r int
-	var data []interface{}
+	for {
+		var underflow bool
 
-	//this collection will attempt to create a new underlying data structure and
-	// down-size it if it's grown more than necessary
-	length = len(q.data)
-	newSize = int(math.Trunc(float64(length)/float64(q.growSize)) * float64(q.growSize))
-	if r = length % q.growSize; r > 0 || newSize == 0 {
-		newSize += q.growSize
+		if item, underflow = q.data.popFront(); !underflow {
+			q.stats.Dequeued(1)
+			q.stats.SendSignal(q.signalOut, ConfigSignalTimeout)
+			q.changed.Notify()
+			return
+		}
+		if err = q.wait(ctx); err != nil {
+			return
+		}
 	}
-	data = make([]interface{}, len(q.data), newSize)
-	copy(data, q.data[:len(q.data)])
-	q.data = data
 }
 
-func (q *queueInfinite) Dequeue() (item interface{}, underflow bool) {
+func (q *queueInfinite[T]) DequeueMultiple(n int) (items []T) {
 	q.Lock()
 	defer q.Unlock()
 
-	item, q.data, underflow = internal.Dequeue(q.data)
-	internal.SendSignal(q.signalOut, ConfigSignalTimeout)
+	if items = q.dequeueMultiple(n); len(items) <= 0 {
+		q.stats.Underflow()
+	}
 
 	return
 }
 
-func (q *queueInfinite) DequeueMultiple(n int) (items []interface{}) {
+func (q *queueInfinite[T]) DequeueMultipleCtx(ctx context.Context, n int) (items []T, err error) {
 	q.Lock()
 	defer q.Unlock()
 
-	var underflow bool
+	for len(items) < n {
+		items = append(items, q.dequeueMultiple(n-len(items))...)
+		if len(items) >= n {
+			break
+		}
+		if err = q.wait(ctx); err != nil {
+			return
+		}
+	}
+
+	return
+}
 
-	if items, q.data, underflow = internal.DequeueMultiple(n, q.data); !underflow {
-		internal.SendSignal(q.signalOut, ConfigSignalTimeout)
+func (q *queueInfinite[T]) Flush() (items []T) {
+	q.Lock()
+	defer q.Unlock()
+
+	if items = q.dequeueMultiple(q.data.size); len(items) <= 0 {
+		q.stats.Underflow()
 	}
 
 	return
 }
 
-func (q *queueInfinite) Flush() (items []interface{}) {
+func (q *queueInfinite[T]) Enqueue(item T) (overflow bool) {
 	q.Lock()
 	defer q.Unlock()
 
-	var underflow bool
+	q.enqueue(item)
+
+	return
+}
+
+func (q *queueInfinite[T]) EnqueueCtx(ctx context.Context, item T) (err error) {
+	q.Lock()
+	defer q.Unlock()
 
-	if items, q.data, underflow = internal.DequeueMultiple(cap(q.data), q.data); !underflow {
-		internal.SendSignal(q.signalOut, ConfigSignalTimeout)
+	//KIM: an infinite queue will never overflow, so there's no need
+	// to wait; the only failure is if the queue is closed
+	if q.closed {
+		return goqueue.ErrQueueClosed
 	}
+	q.enqueue(item)
 
 	return
 }
 
-func (q *queueInfinite) Enqueue(item interface{}) (overflow bool) {
+func (q *queueInfinite[T]) EnqueueMultiple(items []T) (remainingElements []T, overflow bool) {
 	q.Lock()
 	defer q.Unlock()
 
-	q.data = enqueue(q.data, item, q.growSize)
-	internal.SendSignal(q.signalIn, ConfigSignalTimeout)
+	for _, item := range items {
+		q.enqueue(item)
+	}
 
 	return
 }
 
-func (q *queueInfinite) EnqueueMultiple(items []interface{}) (remainingElements []interface{}, overflow bool) {
+func (q *queueInfinite[T]) EnqueueMultipleCtx(ctx context.Context, items []T) (remainingElements []T, err error) {
 	q.Lock()
 	defer q.Unlock()
 
+	if q.closed {
+		return items, goqueue.ErrQueueClosed
+	}
 	for _, item := range items {
-		q.data = enqueue(q.data, item, q.growSize)
-		internal.SendSignal(q.signalIn, ConfigSignalTimeout)
+		q.enqueue(item)
+	}
+
+	return
+}
+
+func (q *queueInfinite[T]) EnqueueInFront(item T) (overflow bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	if grew := q.data.pushFront(item); grew {
+		q.stats.Resize()
+	}
+	q.stats.Enqueued(1, q.data.size)
+	q.stats.SendSignal(q.signalIn, ConfigSignalTimeout)
+	q.changed.Notify()
+
+	return
+}
+
+func (q *queueInfinite[T]) DequeueBack() (item T, underflow bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	item, underflow = q.data.popBack()
+	q.stats.SendSignal(q.signalOut, ConfigSignalTimeout)
+	if underflow {
+		q.stats.Underflow()
+		return
+	}
+	q.stats.Dequeued(1)
+	q.changed.Notify()
+
+	return
+}
+
+func (q *queueInfinite[T]) DequeueMultipleBack(n int) (items []T) {
+	q.Lock()
+	defer q.Unlock()
+
+	var underflow bool
+
+	if items, underflow = q.data.popBackMultiple(n); underflow {
+		q.stats.Underflow()
+		return
+	}
+	q.stats.Dequeued(len(items))
+	q.stats.SendSignal(q.signalOut, ConfigSignalTimeout)
+	q.changed.Notify()
+
+	return
+}
+
+func (q *queueInfinite[T]) DequeueIf(pred func(item T) bool) (item T, underflow bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	if item, underflow = q.data.front(); underflow {
+		q.stats.Underflow()
+		return
+	}
+	if !pred(item) {
+		var zero T
+
+		return zero, true
+	}
+	q.data.popFront()
+	q.stats.Dequeued(1)
+	q.stats.SendSignal(q.signalOut, ConfigSignalTimeout)
+	q.changed.Notify()
+
+	return
+}
+
+func (q *queueInfinite[T]) DequeueWhile(pred func(item T) bool, max int) (items []T) {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.data.size <= 0 {
+		q.stats.Underflow()
+		return
+	}
+	for len(items) < max {
+		item, underflow := q.data.front()
+		if underflow || !pred(item) {
+			break
+		}
+		q.data.popFront()
+		items = append(items, item)
+	}
+	if len(items) > 0 {
+		q.stats.Dequeued(len(items))
+		q.stats.SendSignal(q.signalOut, ConfigSignalTimeout)
+		q.changed.Notify()
 	}
 
 	return
 }
 
-func (q *queueInfinite) EnqueueInFront(item interface{}) (overflow bool) {
+func (q *queueInfinite[T]) RemoveIf(pred func(item T) bool) (items []T) {
 	q.Lock()
 	defer q.Unlock()
 
-	q.data = enqueueInFront(q.data, item, q.growSize)
-	internal.SendSignal(q.signalIn, ConfigSignalTimeout)
+	if items = q.data.removeIf(pred); len(items) > 0 {
+		q.stats.Dequeued(len(items))
+		q.stats.SendSignal(q.signalOut, ConfigSignalTimeout)
+		q.changed.Notify()
+	}
+
+	return
+}
+
+func (q *queueInfinite[T]) Find(pred func(item T) bool) (index int, item T, ok bool) {
+	q.RLock()
+	defer q.RUnlock()
+	return q.data.find(pred)
+}
 
+func (q *queueInfinite[T]) Contains(pred func(item T) bool) (ok bool) {
+	q.RLock()
+	defer q.RUnlock()
+	_, _, ok = q.data.find(pred)
 	return
 }
 
-func (q *queueInfinite) Length() (size int) {
+func (q *queueInfinite[T]) Length() (size int) {
 	q.RLock()
 	defer q.RUnlock()
-	return len(q.data)
+	return q.data.size
 }
 
-func (q *queueInfinite) GetSignalIn() (signal <-chan struct{}) {
+func (q *queueInfinite[T]) GetSignalIn() (signal <-chan struct{}) {
 	q.RLock()
 	defer q.RUnlock()
 	return q.signalIn
 }
 
-func (q *queueInfinite) GetSignalOut() (signal <-chan struct{}) {
+func (q *queueInfinite[T]) GetSignalOut() (signal <-chan struct{}) {
 	q.RLock()
 	defer q.RUnlock()
 	return q.signalOut
 }
 
-func (q *queueInfinite) Peek() (items []interface{}) {
+func (q *queueInfinite[T]) Peek() (items []T) {
 	q.RLock()
 	defer q.RUnlock()
+	return q.data.peekFront(q.data.size)
+}
 
-	for i := 0; i < len(q.data); i++ {
-		items = append(items, q.data[i])
-	}
+func (q *queueInfinite[T]) PeekHead() (item T, underflow bool) {
+	q.RLock()
+	defer q.RUnlock()
+	return q.data.front()
+}
 
-	return
+func (q *queueInfinite[T]) PeekHeadCtx(ctx context.Context) (item T, err error) {
+	q.Lock()
+	defer q.Unlock()
+
+	for q.data.size <= 0 {
+		if err = q.wait(ctx); err != nil {
+			return
+		}
+	}
+	item, _ = q.data.front()
+	return item, nil
 }
 
-func (q *queueInfinite) PeekHead() (item interface{}, underflow bool) {
+func (q *queueInfinite[T]) PeekFromHead(n int) (items []T) {
 	q.RLock()
 	defer q.RUnlock()
+	return q.peekFromHead(n)
+}
 
-	if len(q.data) <= 0 {
-		return nil, true
+func (q *queueInfinite[T]) PeekFromHeadCtx(ctx context.Context, n int) (items []T, err error) {
+	q.Lock()
+	defer q.Unlock()
+
+	for q.data.size < n {
+		if err = q.wait(ctx); err != nil {
+			return q.peekFromHead(n), err
+		}
 	}
-	return q.data[0], false
+	return q.peekFromHead(n), nil
 }
 
-func (q *queueInfinite) PeekFromHead(n int) (items []interface{}) {
+func (q *queueInfinite[T]) PeekTail() (item T, underflow bool) {
 	q.RLock()
 	defer q.RUnlock()
+	return q.data.back()
+}
 
-	if len(q.data) == 0 {
-		return
-	}
-	if n > len(q.data) {
-		n = len(q.data)
-	}
-	for i := 0; i < n; i++ {
-		items = append(items, q.data[i])
-	}
+func (q *queueInfinite[T]) PeekFromTail(n int) (items []T) {
+	q.RLock()
+	defer q.RUnlock()
+	return q.data.peekBack(n)
+}
 
-	return
+func (q *queueInfinite[T]) Stats() (stats goqueue.Stats) {
+	return q.stats.Snapshot()
 }
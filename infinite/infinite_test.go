@@ -10,6 +10,8 @@ import (
 
 	goqueue "github.com/antonio-alexander/go-queue"
 	goqueue_tests "github.com/antonio-alexander/go-queue/tests"
+
+	"github.com/stretchr/testify/assert"
 )
 
 const (
@@ -22,8 +24,6 @@ func init() {
 	rand.Seed(int64(time.Now().Nanosecond()))
 }
 
-//TODO: test the growing of the internal slice
-
 func TestInfiniteQueue(t *testing.T) {
 	t.Run("Test Enqueue", infinite_tests.TestEnqueue(t, mustRate, mustTimeout, func() interface {
 		goqueue.Dequeuer
@@ -95,6 +95,48 @@ func TestQueue(t *testing.T) {
 	} {
 		return infinite.New(size)
 	}))
+	t.Run("Test Dequeue Back", goqueue_tests.TestDequeueBack(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.DequeueFromBacker
+	} {
+		return infinite.New(size)
+	}))
+	t.Run("Test Peek From Tail", goqueue_tests.TestPeekFromTail(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.PeekFromTailer
+	} {
+		return infinite.New(size)
+	}))
+	t.Run("Test Remover", goqueue_tests.TestRemover(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Remover
+		goqueue.Statser
+	} {
+		return infinite.New(size)
+	}))
+	t.Run("Test Conditional Dequeue", goqueue_tests.TestConditionalDequeue(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.ConditionalDequeuer
+	} {
+		return infinite.New(size)
+	}))
+	t.Run("Test Conditional Dequeue Asynchronous", goqueue_tests.TestConditionalDequeueAsync(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Peeker
+		goqueue.ConditionalDequeuer
+	} {
+		return infinite.New(size)
+	}))
 	//configure the timeout to something since the default is 0 and this
 	// test would otherwise fail because the signal channels aren't buffered
 	infinite.ConfigSignalTimeout = 1 * time.Millisecond
@@ -106,6 +148,21 @@ func TestQueue(t *testing.T) {
 	} {
 		return infinite.New(size)
 	}))
+	t.Run("Test Dequeue Ctx", goqueue_tests.TestDequeueCtx(t, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.DequeuerCtx
+	} {
+		return infinite.New(size)
+	}))
+	t.Run("Test Peek Ctx", goqueue_tests.TestPeekCtx(t, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Length
+		goqueue.PeekerCtx
+	} {
+		return infinite.New(size)
+	}))
 	t.Run("Test Length", goqueue_tests.TestLength(t, func(size int) interface {
 		goqueue.Owner
 		goqueue.Enqueuer
@@ -128,4 +185,96 @@ func TestQueue(t *testing.T) {
 	} {
 		return infinite.New(size)
 	}))
+	t.Run("Test Stats", goqueue_tests.TestStats(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Statser
+	} {
+		return infinite.New(size)
+	}))
+}
+
+func TestInfiniteQueueOf(t *testing.T) {
+	t.Run("Test Queue Of", goqueue_tests.TestQueueOf(t, func(size int) interface {
+		goqueue.OwnerOf[*goqueue.Example]
+		goqueue.EnqueuerOf[*goqueue.Example]
+		goqueue.DequeuerOf[*goqueue.Example]
+		goqueue.PeekerOf[*goqueue.Example]
+	} {
+		return infinite.NewOf[*goqueue.Example](size)
+	}))
+	//KIM: a queue of empty interface satisfies the non-generic interfaces
+	// so the existing test suites can be used as-is
+	t.Run("Test Enqueue In Front", infinite_tests.TestEnqueueInFront(t, mustRate, mustTimeout, func() interface {
+		goqueue.Dequeuer
+		goqueue.EnqueueInFronter
+		goqueue.Enqueuer
+		goqueue.Owner
+	} {
+		return infinite.NewOf[interface{}](queueGrowSize)
+	}))
+	t.Run("Test Queue", goqueue_tests.TestQueue(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return infinite.NewOf[interface{}](size)
+	}))
+}
+
+func TestChunks(t *testing.T) {
+	const chunkSize = 4
+
+	//enqueue and enqueue in front across several chunk boundaries and
+	// confirm that order is maintained as the chunks are released
+	q := infinite.NewOf[int](chunkSize)
+	defer q.Close()
+	for i := 0; i < 5*chunkSize; i++ {
+		q.Enqueue(i)
+	}
+	for i := 1; i <= 2*chunkSize; i++ {
+		q.EnqueueInFront(-i)
+	}
+	expected := make([]int, 0, 7*chunkSize)
+	for i := 2 * chunkSize; i >= 1; i-- {
+		expected = append(expected, -i)
+	}
+	for i := 0; i < 5*chunkSize; i++ {
+		expected = append(expected, i)
+	}
+	assert.Equal(t, expected, q.Peek())
+	assert.Equal(t, expected[:chunkSize+1], q.PeekFromHead(chunkSize+1))
+	assert.Equal(t, uint64(7), q.Stats().Resizes)
+	for i := 0; i < len(expected); i++ {
+		item, underflow := q.Dequeue()
+		assert.False(t, underflow)
+		assert.Equal(t, expected[i], item)
+		assert.Equal(t, len(expected)-i-1, q.Length())
+	}
+	_, underflow := q.Dequeue()
+	assert.True(t, underflow)
+
+	//confirm that the queue can be re-used once it's empty
+	q.EnqueueInFront(1)
+	q.Enqueue(2)
+	assert.Equal(t, []int{1, 2}, q.Flush())
+}
+
+func BenchmarkEnqueueDequeue(b *testing.B) {
+	//KIM: the signal timeout would otherwise dominate the benchmark
+	// since no-one is listening to the signal channels
+	signalTimeout := infinite.ConfigSignalTimeout
+	infinite.ConfigSignalTimeout = 0
+	defer func() { infinite.ConfigSignalTimeout = signalTimeout }()
+
+	q := infinite.New(queueGrowSize)
+	defer q.Close()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.Enqueue(i)
+	}
+	for i := 0; i < b.N; i++ {
+		q.Dequeue()
+	}
 }
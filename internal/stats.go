@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"sync/atomic"
+	"time"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+)
+
+//Stats can be used by queue implementations to count the operations
+// performed on the queue with atomic counters such that a snapshot can be
+// taken without locking the queue. It should be allocated (e.g. via new())
+// such that the counters are aligned for atomic operations
+type Stats struct {
+	enqueued           uint64
+	dequeued           uint64
+	overflow           uint64
+	underflow          uint64
+	lossyDiscards      uint64
+	highWaterMark      uint64
+	resizes            uint64
+	garbageCollections uint64
+	droppedSignals     uint64
+}
+
+//Enqueued will add n to the number of items enqueued and update the
+// high-water mark with the given size of the queue
+func (s *Stats) Enqueued(n, size int) {
+	atomic.AddUint64(&s.enqueued, uint64(n))
+	for {
+		highWaterMark := atomic.LoadUint64(&s.highWaterMark)
+		if uint64(size) <= highWaterMark ||
+			atomic.CompareAndSwapUint64(&s.highWaterMark, highWaterMark, uint64(size)) {
+			return
+		}
+	}
+}
+
+//Dequeued will add n to the number of items dequeued
+func (s *Stats) Dequeued(n int) {
+	atomic.AddUint64(&s.dequeued, uint64(n))
+}
+
+//Overflow will increment the number of operations that overflowed
+func (s *Stats) Overflow() {
+	atomic.AddUint64(&s.overflow, 1)
+}
+
+//Underflow will increment the number of operations that underflowed
+func (s *Stats) Underflow() {
+	atomic.AddUint64(&s.underflow, 1)
+}
+
+//LossyDiscard will increment the number of items discarded by a lossy
+// enqueue
+func (s *Stats) LossyDiscard() {
+	atomic.AddUint64(&s.lossyDiscards, 1)
+}
+
+//Resize will increment the number of times the queue has been resized
+func (s *Stats) Resize() {
+	atomic.AddUint64(&s.resizes, 1)
+}
+
+//GarbageCollect will increment the number of times the queue has been
+// garbage collected
+func (s *Stats) GarbageCollect() {
+	atomic.AddUint64(&s.garbageCollections, 1)
+}
+
+//SendSignal will send a signal using SendSignal() and increment the number
+// of dropped signals if the signal couldn't be sent
+func (s *Stats) SendSignal(signal chan struct{}, timeout ...time.Duration) bool {
+	if sent := SendSignal(signal, timeout...); sent {
+		return true
+	}
+	atomic.AddUint64(&s.droppedSignals, 1)
+	return false
+}
+
+//Snapshot will return the current value of all of the counters, keep in
+// mind that the counters are read individually
+func (s *Stats) Snapshot() goqueue.Stats {
+	return goqueue.Stats{
+		Enqueued:           atomic.LoadUint64(&s.enqueued),
+		Dequeued:           atomic.LoadUint64(&s.dequeued),
+		Overflow:           atomic.LoadUint64(&s.overflow),
+		Underflow:          atomic.LoadUint64(&s.underflow),
+		LossyDiscards:      atomic.LoadUint64(&s.lossyDiscards),
+		HighWaterMark:      atomic.LoadUint64(&s.highWaterMark),
+		Resizes:            atomic.LoadUint64(&s.resizes),
+		GarbageCollections: atomic.LoadUint64(&s.garbageCollections),
+		DroppedSignals:     atomic.LoadUint64(&s.droppedSignals),
+	}
+}
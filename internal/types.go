@@ -0,0 +1,27 @@
+package internal
+
+//Notifier can be used to broadcast that the state of a queue has changed
+// to any number of waiters; unlike the signal channels, a notification
+// can't be missed because the channel is closed rather than sent to. It's
+// not safe for concurrent use and expects to be protected by the lock of
+// the queue that owns it
+type Notifier struct {
+	changed chan struct{}
+}
+
+//Wait will return a channel that will be closed the next time Notify()
+// is called, the channel is only created if someone is waiting
+func (n *Notifier) Wait() <-chan struct{} {
+	if n.changed == nil {
+		n.changed = make(chan struct{})
+	}
+	return n.changed
+}
+
+//Notify will wake up anyone waiting on the channel returned by Wait()
+func (n *Notifier) Notify() {
+	if n.changed != nil {
+		close(n.changed)
+		n.changed = nil
+	}
+}
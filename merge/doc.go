@@ -0,0 +1,11 @@
+// Copyright 2022 antonio-alexander. All rights reserved.
+// Use of this source code is governed by an MIT
+// license that can be found in the LICENSE file.
+
+/*
+	Package merge provides a queue that presents several queues as one
+	(fan-in), items are dequeued from the queues in round-robin, priority
+	or weighted order and blocking dequeues wait on the signals of all of
+	the queues at once
+*/
+package merge
@@ -0,0 +1,182 @@
+package merge
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+)
+
+type queueMerge struct {
+	sync.Mutex
+	configuration
+	sources []Source
+	next    int
+	credit  int
+}
+
+//New can be used to create a queue that dequeues from each of the given
+// queues, the queues aren't owned by the merged queue and should be closed
+// by the caller; items can still be enqueued into the queues directly
+func New(sources []Source, options ...Option) interface {
+	goqueue.Dequeuer
+	goqueue.DequeuerCtx
+} {
+	m := &queueMerge{
+		configuration: configuration{
+			pollRate: DefaultPollRate,
+		},
+		sources: sources,
+	}
+	for _, option := range options {
+		option(&m.configuration)
+	}
+	//KIM: round robin is weighted round robin where every queue
+	// has a weight of one
+	weights := make([]int, len(sources))
+	for i := range weights {
+		weights[i] = 1
+		if m.policy == PolicyWeighted && i < len(m.weights) && m.weights[i] > 1 {
+			weights[i] = m.weights[i]
+		}
+	}
+	m.weights = weights
+	if len(weights) > 0 {
+		m.credit = weights[0]
+	}
+	return m
+}
+
+//order will return the index of the queue to try j-th, it expects the
+// queue to be locked
+func (m *queueMerge) order(j int) int {
+	if m.policy == PolicyPriority {
+		return j
+	}
+	return (m.next + j) % len(m.sources)
+}
+
+//dequeued will update which queue is next once an item has been dequeued
+// from the queue at index i, it expects the queue to be locked
+func (m *queueMerge) dequeued(i int) {
+	if m.policy == PolicyPriority {
+		return
+	}
+	if i != m.next {
+		m.next, m.credit = i, m.weights[i]
+	}
+	if m.credit--; m.credit <= 0 {
+		m.next = (i + 1) % len(m.sources)
+		m.credit = m.weights[m.next]
+	}
+}
+
+func (m *queueMerge) dequeue() (item interface{}, underflow bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	for j := 0; j < len(m.sources); j++ {
+		i := m.order(j)
+		if item, underflow = m.sources[i].Dequeue(); !underflow {
+			m.dequeued(i)
+			return
+		}
+	}
+	return nil, true
+}
+
+//wait will block until any of the queues signals, the context is done or
+// the poll rate has passed; it will return ErrQueueClosed if all of the
+// queues are closed. closed is used to ignore signals that have already
+// been found to be closed
+func (m *queueMerge) wait(ctx context.Context, closed map[<-chan struct{}]bool) error {
+	tPoll := time.NewTimer(m.pollRate)
+	defer tPoll.Stop()
+	cases := []reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(tPoll.C)},
+	}
+	signals := make([]<-chan struct{}, 0, len(m.sources))
+	for _, source := range m.sources {
+		signal := source.GetSignalIn()
+		if signal == nil || closed[signal] {
+			continue
+		}
+		signals = append(signals, signal)
+		cases = append(cases, reflect.SelectCase{
+			Dir:  reflect.SelectRecv,
+			Chan: reflect.ValueOf(signal),
+		})
+	}
+	if len(signals) == 0 {
+		return goqueue.ErrQueueClosed
+	}
+	switch chosen, _, ok := reflect.Select(cases); chosen {
+	case 0:
+		return ctx.Err()
+	case 1:
+	default:
+		//KIM: a signal is closed if its queue is closed or re-created
+		// (e.g. re-sized), the signal will be acquired again
+		if !ok {
+			closed[signals[chosen-2]] = true
+		}
+	}
+	return nil
+}
+
+func (m *queueMerge) Dequeue() (item interface{}, underflow bool) {
+	return m.dequeue()
+}
+
+func (m *queueMerge) DequeueCtx(ctx context.Context) (item interface{}, err error) {
+	closed := make(map[<-chan struct{}]bool)
+	for {
+		var underflow bool
+
+		if item, underflow = m.dequeue(); !underflow {
+			return
+		}
+		if err = m.wait(ctx, closed); err != nil {
+			return
+		}
+	}
+}
+
+func (m *queueMerge) DequeueMultiple(n int) (items []interface{}) {
+	for len(items) < n {
+		item, underflow := m.dequeue()
+		if underflow {
+			break
+		}
+		items = append(items, item)
+	}
+	return
+}
+
+func (m *queueMerge) DequeueMultipleCtx(ctx context.Context, n int) (items []interface{}, err error) {
+	closed := make(map[<-chan struct{}]bool)
+	for len(items) < n {
+		item, underflow := m.dequeue()
+		if !underflow {
+			items = append(items, item)
+			continue
+		}
+		if err = m.wait(ctx, closed); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (m *queueMerge) Flush() (items []interface{}) {
+	m.Lock()
+	defer m.Unlock()
+
+	for j := 0; j < len(m.sources); j++ {
+		items = append(items, m.sources[m.order(j)].Flush()...)
+	}
+	return
+}
@@ -0,0 +1,148 @@
+package merge_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+	finite "github.com/antonio-alexander/go-queue/finite"
+	infinite "github.com/antonio-alexander/go-queue/infinite"
+	merge "github.com/antonio-alexander/go-queue/merge"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	mustTimeout = time.Second
+	mustRate    = time.Millisecond
+	casef       = "case: %s"
+)
+
+func TestMerge(t *testing.T) {
+	t.Run("Test Policy", func(t *testing.T) {
+		cases := map[string]struct {
+			iOptions []merge.Option
+			iItems   [][]interface{}
+			oItems   []interface{}
+		}{
+			"round robin": {
+				iItems: [][]interface{}{{"a1", "a2", "a3"}, {"b1"}, {"c1", "c2"}},
+				oItems: []interface{}{"a1", "b1", "c1", "a2", "c2", "a3"},
+			},
+			"priority": {
+				iOptions: []merge.Option{merge.WithPolicy(merge.PolicyPriority)},
+				iItems:   [][]interface{}{{"a1", "a2"}, {"b1", "b2"}, {"c1"}},
+				oItems:   []interface{}{"a1", "a2", "b1", "b2", "c1"},
+			},
+			"weighted": {
+				iOptions: []merge.Option{merge.WithPolicy(merge.PolicyWeighted), merge.WithWeights(3, 1)},
+				iItems:   [][]interface{}{{"a1", "a2", "a3", "a4", "a5"}, {"b1", "b2"}, {"c1", "c2"}},
+				oItems:   []interface{}{"a1", "a2", "a3", "b1", "c1", "a4", "a5", "b2", "c2"},
+			},
+			"weights ignored": {
+				iOptions: []merge.Option{merge.WithWeights(3, 1)},
+				iItems:   [][]interface{}{{"a1", "a2"}, {"b1", "b2"}},
+				oItems:   []interface{}{"a1", "b1", "a2", "b2"},
+			},
+		}
+		for cDesc, c := range cases {
+			//enqueue items into each queue and confirm that they're
+			// dequeued in the order described by the policy
+			var sources []merge.Source
+			for _, items := range c.iItems {
+				q := finite.New(10)
+				defer q.Close()
+				_, overflow := q.EnqueueMultiple(items)
+				assert.False(t, overflow, casef, cDesc)
+				sources = append(sources, q)
+			}
+			m := merge.New(sources, c.iOptions...)
+			var items []interface{}
+			for {
+				item, underflow := m.Dequeue()
+				if underflow {
+					break
+				}
+				items = append(items, item)
+			}
+			assert.Equal(t, c.oItems, items, casef, cDesc)
+		}
+	})
+	t.Run("Test Multiple", func(t *testing.T) {
+		//confirm that dequeue multiple follows the policy and flush
+		// removes the items from every queue
+		q1, q2 := finite.New(10), infinite.New(10)
+		defer q1.Close()
+		defer q2.Close()
+		m := merge.New([]merge.Source{q1, q2})
+		q1.EnqueueMultiple([]interface{}{1, 3, 5})
+		q2.EnqueueMultiple([]interface{}{2, 4})
+		assert.Equal(t, []interface{}{1, 2, 3}, m.DequeueMultiple(3))
+		assert.Equal(t, []interface{}{4, 5}, m.Flush())
+		assert.Empty(t, m.DequeueMultiple(1))
+		assert.Empty(t, m.Flush())
+	})
+	t.Run("Test Dequeue Ctx", func(t *testing.T) {
+		//wait for an item and enqueue into each of the queues and confirm
+		// that the wait is woken no matter which queue is enqueued into
+		q1, q2 := finite.New(10), infinite.New(10)
+		defer q1.Close()
+		defer q2.Close()
+		m := merge.New([]merge.Source{q1, q2}, merge.WithPollRate(time.Hour))
+		for _, q := range []goqueue.Enqueuer{q1, q2} {
+			go func(q goqueue.Enqueuer) {
+				time.Sleep(10 * mustRate)
+				q.Enqueue(1)
+			}(q)
+			ctx, cancel := context.WithTimeout(context.TODO(), mustTimeout)
+			item, err := m.DequeueCtx(ctx)
+			cancel()
+			assert.Nil(t, err)
+			assert.Equal(t, 1, item)
+		}
+
+		//confirm that dequeue multiple waits for n items
+		go func() {
+			for i := 0; i < 4; i++ {
+				time.Sleep(mustRate)
+				q1.Enqueue(i)
+			}
+		}()
+		ctx, cancel := context.WithTimeout(context.TODO(), mustTimeout)
+		defer cancel()
+		items, err := m.DequeueMultipleCtx(ctx, 4)
+		assert.Nil(t, err)
+		assert.Equal(t, []interface{}{0, 1, 2, 3}, items)
+
+		//confirm that the context error is returned if no item is
+		// available in time
+		ctx, cancel = context.WithTimeout(context.TODO(), 10*mustRate)
+		defer cancel()
+		_, err = m.DequeueCtx(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+	t.Run("Test Closed", func(t *testing.T) {
+		//close one queue and confirm that the merged queue still waits on
+		// the other, then close it and confirm ErrQueueClosed is returned
+		q1, q2 := finite.New(10), infinite.New(10)
+		m := merge.New([]merge.Source{q1, q2})
+		q1.Close()
+		go func() {
+			time.Sleep(10 * mustRate)
+			q2.Enqueue(1)
+		}()
+		ctx, cancel := context.WithTimeout(context.TODO(), mustTimeout)
+		defer cancel()
+		item, err := m.DequeueCtx(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, item)
+		go func() {
+			time.Sleep(10 * mustRate)
+			q2.Close()
+		}()
+		items, err := m.DequeueMultipleCtx(ctx, 1)
+		assert.ErrorIs(t, err, goqueue.ErrQueueClosed)
+		assert.Empty(t, items)
+	})
+}
@@ -0,0 +1,71 @@
+package merge
+
+import (
+	"time"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+)
+
+//DefaultPollRate is how often a blocking dequeue will check the queues in
+// case a signal was missed if no rate is configured
+const DefaultPollRate = 10 * time.Millisecond
+
+//Source describes a queue that can be merged, both the finite and infinite
+// queues satisfy this interface
+type Source interface {
+	goqueue.Dequeuer
+	goqueue.Event
+}
+
+//Policy describes the order in which items are dequeued from the queues
+type Policy int
+
+const (
+	//PolicyRoundRobin will dequeue an item from each queue in turn
+	PolicyRoundRobin Policy = iota
+	//PolicyPriority will dequeue from the queues in the order they were
+	// provided, a queue is only dequeued from if the queues before it
+	// are empty
+	PolicyPriority
+	//PolicyWeighted will dequeue up to weight items from each queue in
+	// turn
+	PolicyWeighted
+)
+
+//Option can be used to configure a merged queue on creation
+type Option func(*configuration)
+
+type configuration struct {
+	policy   Policy
+	weights  []int
+	pollRate time.Duration
+}
+
+//WithPolicy will configure the order in which items are dequeued from the
+// queues, the default is PolicyRoundRobin
+func WithPolicy(policy Policy) Option {
+	return func(c *configuration) {
+		c.policy = policy
+	}
+}
+
+//WithWeights will configure the weight of each queue (in the order they
+// were provided) for PolicyWeighted, queues without a weight or with a
+// weight less than one have a weight of one
+func WithWeights(weights ...int) Option {
+	return func(c *configuration) {
+		c.weights = weights
+	}
+}
+
+//WithPollRate will configure how often a blocking dequeue will check the
+// queues in case a signal was missed, if the rate isn't greater than zero
+// the DefaultPollRate is used
+func WithPollRate(rate time.Duration) Option {
+	return func(c *configuration) {
+		if rate <= 0 {
+			rate = DefaultPollRate
+		}
+		c.pollRate = rate
+	}
+}
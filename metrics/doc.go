@@ -0,0 +1,10 @@
+// Copyright 2022 antonio-alexander. All rights reserved.
+// Use of this source code is governed by an MIT
+// license that can be found in the LICENSE file.
+
+/*
+	Package metrics provides a registry of named queues that can publish
+	their length, capacity, throughput and time-in-queue via expvar or as
+	Prometheus text exposition via an http.Handler
+*/
+package metrics
@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//timing keeps track of the items that go through an instrumented queue and
+// how long they spent in the queue
+type timing struct {
+	sync.Mutex
+	enqueued uint64
+	dequeued uint64
+	count    uint64
+	sum      float64
+	bounds   []float64
+	buckets  []uint64
+}
+
+func newTiming(bounds []float64) *timing {
+	return &timing{
+		bounds:  bounds,
+		buckets: make([]uint64, len(bounds)),
+	}
+}
+
+func (t *timing) enqueue(n int) {
+	t.Lock()
+	defer t.Unlock()
+	t.enqueued += uint64(n)
+}
+
+func (t *timing) dequeue(enqueued ...time.Time) {
+	t.Lock()
+	defer t.Unlock()
+
+	now := time.Now()
+	for _, enqueued := range enqueued {
+		seconds := now.Sub(enqueued).Seconds()
+		t.dequeued++
+		t.count++
+		t.sum += seconds
+		for i, bound := range t.bounds {
+			if seconds <= bound {
+				t.buckets[i]++
+			}
+		}
+	}
+}
+
+func (t *timing) snapshot() (enqueued, dequeued uint64, timeInQueue *TimeInQueue) {
+	t.Lock()
+	defer t.Unlock()
+
+	timeInQueue = &TimeInQueue{
+		Count:   t.count,
+		Sum:     t.sum,
+		Buckets: make([]Bucket, 0, len(t.bounds)),
+	}
+	for i, bound := range t.bounds {
+		timeInQueue.Buckets = append(timeInQueue.Buckets, Bucket{
+			UpperBound: bound,
+			Count:      t.buckets[i],
+		})
+	}
+	return t.enqueued, t.dequeued, timeInQueue
+}
+
+//escapeLabel will escape a label value for the Prometheus text format
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+//writePrometheus will write the metrics in the Prometheus text exposition
+// format, queues are sorted by name such that the output is stable
+func writePrometheus(w io.Writer, metrics map[string]Metrics) {
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	family := func(name, help, kind string, write func(name string, m Metrics, label string)) {
+		fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+		fmt.Fprintf(w, "# TYPE %s %s\n", name, kind)
+		for _, queue := range names {
+			write(name, metrics[queue], `queue="`+escapeLabel(queue)+`"`)
+		}
+	}
+	family("goqueue_length", "The number of items in the queue.", "gauge",
+		func(name string, m Metrics, label string) {
+			fmt.Fprintf(w, "%s{%s} %d\n", name, label, m.Length)
+		})
+	family("goqueue_capacity", "The number of items the queue can hold.", "gauge",
+		func(name string, m Metrics, label string) {
+			if m.Capacity > 0 {
+				fmt.Fprintf(w, "%s{%s} %d\n", name, label, m.Capacity)
+			}
+		})
+	family("goqueue_enqueued_total", "The number of items enqueued.", "counter",
+		func(name string, m Metrics, label string) {
+			fmt.Fprintf(w, "%s{%s} %d\n", name, label, m.Enqueued)
+		})
+	family("goqueue_dequeued_total", "The number of items dequeued.", "counter",
+		func(name string, m Metrics, label string) {
+			fmt.Fprintf(w, "%s{%s} %d\n", name, label, m.Dequeued)
+		})
+	family("goqueue_time_in_queue_seconds", "How long items spent in the queue before they were dequeued.", "histogram",
+		func(name string, m Metrics, label string) {
+			if m.TimeInQueue == nil {
+				return
+			}
+			for _, bucket := range m.TimeInQueue.Buckets {
+				fmt.Fprintf(w, "%s_bucket{%s,le=\"%s\"} %d\n", name, label, formatFloat(bucket.UpperBound), bucket.Count)
+			}
+			fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, label, m.TimeInQueue.Count)
+			fmt.Fprintf(w, "%s_sum{%s} %s\n", name, label, formatFloat(m.TimeInQueue.Sum))
+			fmt.Fprintf(w, "%s_count{%s} %d\n", name, label, m.TimeInQueue.Count)
+		})
+}
@@ -0,0 +1,198 @@
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"sync"
+	"time"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+	finite "github.com/antonio-alexander/go-queue/finite"
+)
+
+type entry struct {
+	queue  goqueue.Length
+	timing *timing
+}
+
+type registry struct {
+	sync.RWMutex
+	entries map[string]*entry
+}
+
+//New can be used to create a registry of queues, the registry implements
+// expvar.Var such that it can be published via expvar.Publish() and
+// http.Handler such that it can serve the Prometheus text exposition format
+func New() interface {
+	Registerer
+	Snapshotter
+	expvar.Var
+	http.Handler
+} {
+	return &registry{
+		entries: make(map[string]*entry),
+	}
+}
+
+func (r *registry) register(name string, e *entry) (err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.entries[name]; ok {
+		return ErrAlreadyRegistered
+	}
+	r.entries[name] = e
+
+	return
+}
+
+func (r *registry) Register(name string, queue goqueue.Length) (err error) {
+	return r.register(name, &entry{queue: queue})
+}
+
+func (r *registry) Instrument(name string, queue Instrumentable) (instrumented interface {
+	goqueue.Owner
+	goqueue.Enqueuer
+	goqueue.Dequeuer
+	goqueue.Length
+}, err error) {
+	e := &entry{queue: queue, timing: newTiming(DefaultBuckets)}
+	if err = r.register(name, e); err != nil {
+		return nil, err
+	}
+	return &queueInstrumented{queue: queue, timing: e.timing}, nil
+}
+
+func (r *registry) Unregister(name string) {
+	r.Lock()
+	defer r.Unlock()
+	delete(r.entries, name)
+}
+
+func (r *registry) Snapshot() (metrics map[string]Metrics) {
+	r.RLock()
+	defer r.RUnlock()
+
+	metrics = make(map[string]Metrics, len(r.entries))
+	for name, e := range r.entries {
+		m := Metrics{Length: e.queue.Length()}
+		if capacity, ok := e.queue.(finite.Capacity); ok {
+			m.Capacity = capacity.Capacity()
+		}
+		if e.timing != nil {
+			m.Enqueued, m.Dequeued, m.TimeInQueue = e.timing.snapshot()
+		}
+		//KIM: if the queue keeps its own stats, they're preferred since
+		// they include items that didn't go through the instrumented wrapper
+		if statser, ok := e.queue.(goqueue.Statser); ok {
+			stats := statser.Stats()
+			m.Enqueued, m.Dequeued = stats.Enqueued, stats.Dequeued
+		}
+		metrics[name] = m
+	}
+
+	return
+}
+
+//String will return the metrics for all of the registered queues as JSON,
+// it implements expvar.Var
+func (r *registry) String() string {
+	bytes, err := json.Marshal(r.Snapshot())
+	if err != nil {
+		return "{}"
+	}
+	return string(bytes)
+}
+
+//ServeHTTP will write the metrics for all of the registered queues in the
+// Prometheus text exposition format
+func (r *registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	writePrometheus(w, r.Snapshot())
+}
+
+//envelope is what's actually stored in an instrumented queue, it allows
+// the time the item was enqueued to follow the item
+type envelope struct {
+	item     interface{}
+	enqueued time.Time
+}
+
+type queueInstrumented struct {
+	queue  Instrumentable
+	timing *timing
+}
+
+func unwrap(item interface{}) (interface{}, time.Time) {
+	if e, ok := item.(*envelope); ok {
+		return e.item, e.enqueued
+	}
+	return item, time.Now()
+}
+
+func (q *queueInstrumented) dequeueMultiple(items []interface{}) []interface{} {
+	if len(items) <= 0 {
+		return items
+	}
+	enqueued := make([]time.Time, len(items))
+	for i, item := range items {
+		items[i], enqueued[i] = unwrap(item)
+	}
+	q.timing.dequeue(enqueued...)
+	return items
+}
+
+//Close will close the queue if it's an Owner and return the items that
+// remain
+func (q *queueInstrumented) Close() (remainingElements []interface{}) {
+	owner, ok := q.queue.(goqueue.Owner)
+	if !ok {
+		return nil
+	}
+	remainingElements = owner.Close()
+	for i, item := range remainingElements {
+		remainingElements[i], _ = unwrap(item)
+	}
+	return
+}
+
+func (q *queueInstrumented) Dequeue() (item interface{}, underflow bool) {
+	if item, underflow = q.queue.Dequeue(); underflow {
+		return
+	}
+	return q.dequeueMultiple([]interface{}{item})[0], false
+}
+
+func (q *queueInstrumented) DequeueMultiple(n int) (items []interface{}) {
+	return q.dequeueMultiple(q.queue.DequeueMultiple(n))
+}
+
+func (q *queueInstrumented) Flush() (items []interface{}) {
+	return q.dequeueMultiple(q.queue.Flush())
+}
+
+func (q *queueInstrumented) Enqueue(item interface{}) (overflow bool) {
+	if overflow = q.queue.Enqueue(&envelope{item: item, enqueued: time.Now()}); !overflow {
+		q.timing.enqueue(1)
+	}
+	return
+}
+
+func (q *queueInstrumented) EnqueueMultiple(items []interface{}) (remainingElements []interface{}, overflow bool) {
+	now := time.Now()
+	envelopes := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		envelopes = append(envelopes, &envelope{item: item, enqueued: now})
+	}
+	remainingElements, overflow = q.queue.EnqueueMultiple(envelopes)
+	q.timing.enqueue(len(items) - len(remainingElements))
+	for i, item := range remainingElements {
+		remainingElements[i], _ = unwrap(item)
+	}
+	return
+}
+
+func (q *queueInstrumented) Length() (size int) {
+	return q.queue.Length()
+}
@@ -0,0 +1,117 @@
+package metrics_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	finite "github.com/antonio-alexander/go-queue/finite"
+	infinite "github.com/antonio-alexander/go-queue/infinite"
+	metrics "github.com/antonio-alexander/go-queue/metrics"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Run("Register", func(t *testing.T) {
+		r := metrics.New()
+		qFinite, qInfinite := finite.New(10), infinite.New(10)
+		defer qFinite.Close()
+		defer qInfinite.Close()
+		assert.NoError(t, r.Register("finite", qFinite))
+		assert.NoError(t, r.Register("infinite", qInfinite))
+		assert.ErrorIs(t, r.Register("finite", qInfinite), metrics.ErrAlreadyRegistered)
+		qFinite.EnqueueMultiple([]interface{}{1, 2, 3})
+		qFinite.Dequeue()
+		qInfinite.Enqueue(1)
+		snapshot := r.Snapshot()
+		assert.Equal(t, metrics.Metrics{
+			Length:   2,
+			Capacity: 10,
+			Enqueued: 3,
+			Dequeued: 1,
+		}, snapshot["finite"])
+		assert.Equal(t, metrics.Metrics{
+			Length:   1,
+			Enqueued: 1,
+		}, snapshot["infinite"])
+		r.Unregister("infinite")
+		assert.Len(t, r.Snapshot(), 1)
+	})
+	t.Run("Instrument", func(t *testing.T) {
+		const wait = 10 * time.Millisecond
+
+		r := metrics.New()
+		q, err := r.Instrument("instrumented", infinite.New(10))
+		if !assert.NoError(t, err) {
+			return
+		}
+		_, err = r.Instrument("instrumented", infinite.New(10))
+		assert.ErrorIs(t, err, metrics.ErrAlreadyRegistered)
+		q.Enqueue(1)
+		q.EnqueueMultiple([]interface{}{2, 3})
+		time.Sleep(wait)
+		item, underflow := q.Dequeue()
+		assert.False(t, underflow)
+		assert.Equal(t, 1, item)
+		assert.Equal(t, []interface{}{2}, q.DequeueMultiple(1))
+		m := r.Snapshot()["instrumented"]
+		assert.Equal(t, 1, m.Length)
+		assert.Equal(t, uint64(3), m.Enqueued)
+		assert.Equal(t, uint64(2), m.Dequeued)
+		if assert.NotNil(t, m.TimeInQueue) {
+			assert.Equal(t, uint64(2), m.TimeInQueue.Count)
+			assert.GreaterOrEqual(t, m.TimeInQueue.Sum, 2*wait.Seconds())
+			assert.Len(t, m.TimeInQueue.Buckets, len(metrics.DefaultBuckets))
+			assert.Zero(t, m.TimeInQueue.Buckets[0].Count)
+			assert.Equal(t, uint64(2), m.TimeInQueue.Buckets[len(metrics.DefaultBuckets)-1].Count)
+		}
+		assert.Equal(t, []interface{}{3}, q.Close())
+	})
+	t.Run("Expvar", func(t *testing.T) {
+		r := metrics.New()
+		q := finite.New(5)
+		defer q.Close()
+		q.Enqueue(1)
+		assert.NoError(t, r.Register("queue", q))
+		snapshot := map[string]metrics.Metrics{}
+		assert.NoError(t, json.Unmarshal([]byte(r.String()), &snapshot))
+		assert.Equal(t, r.Snapshot(), snapshot)
+	})
+	t.Run("Prometheus", func(t *testing.T) {
+		r := metrics.New()
+		qFinite := finite.New(5)
+		defer qFinite.Close()
+		qFinite.Enqueue(1)
+		assert.NoError(t, r.Register(`fin"ite`, qFinite))
+		qInstrumented, err := r.Instrument("instrumented", infinite.New(1))
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer qInstrumented.Close()
+		qInstrumented.Enqueue(1)
+		qInstrumented.Dequeue()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+		assert.Equal(t, 200, w.Code)
+		assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain"))
+		body := w.Body.String()
+		for _, line := range []string{
+			"# TYPE goqueue_length gauge",
+			`goqueue_length{queue="fin\"ite"} 1`,
+			`goqueue_length{queue="instrumented"} 0`,
+			`goqueue_capacity{queue="fin\"ite"} 5`,
+			`goqueue_enqueued_total{queue="instrumented"} 1`,
+			`goqueue_dequeued_total{queue="instrumented"} 1`,
+			"# TYPE goqueue_time_in_queue_seconds histogram",
+			`goqueue_time_in_queue_seconds_bucket{queue="instrumented",le="+Inf"} 1`,
+			`goqueue_time_in_queue_seconds_count{queue="instrumented"} 1`,
+		} {
+			assert.Contains(t, body, line+"\n")
+		}
+		assert.NotContains(t, body, `goqueue_capacity{queue="instrumented"}`)
+		assert.NotContains(t, body, `goqueue_time_in_queue_seconds_count{queue="fin\"ite"}`)
+	})
+}
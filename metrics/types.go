@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"errors"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+)
+
+//ErrAlreadyRegistered will be returned if a queue is registered with a name
+// that's already in use
+var ErrAlreadyRegistered = errors.New("queue already registered")
+
+//DefaultBuckets are the upper bounds (in seconds) of the buckets used for
+// the time-in-queue histogram
+var DefaultBuckets = []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10, 60}
+
+//Bucket is a single bucket of a histogram, Count is the number of
+// observations less than or equal to the UpperBound (it's cumulative)
+type Bucket struct {
+	UpperBound float64 `json:"upper_bound"`
+	Count      uint64  `json:"count"`
+}
+
+//TimeInQueue is a histogram of how long items spent in a queue (in seconds)
+// before they were dequeued
+type TimeInQueue struct {
+	Count   uint64   `json:"count"`
+	Sum     float64  `json:"sum"`
+	Buckets []Bucket `json:"buckets"`
+}
+
+//Metrics is a snapshot of the metrics for a single queue; Capacity is only
+// available if the queue implements finite.Capacity, Enqueued and Dequeued
+// are only available if the queue implements goqueue.Statser or was
+// instrumented and TimeInQueue is only available if the queue was
+// instrumented
+type Metrics struct {
+	Length      int          `json:"length"`
+	Capacity    int          `json:"capacity,omitempty"`
+	Enqueued    uint64       `json:"enqueued"`
+	Dequeued    uint64       `json:"dequeued"`
+	TimeInQueue *TimeInQueue `json:"time_in_queue,omitempty"`
+}
+
+//Instrumentable describes the queues that can be instrumented
+type Instrumentable interface {
+	goqueue.Enqueuer
+	goqueue.Dequeuer
+	goqueue.Length
+}
+
+//Registerer can be used to add or remove the queues that metrics are
+// published for. Register() will register a queue as is while Instrument()
+// will register the queue and return a wrapper that records the time items
+// spend in the queue (the wrapper should be used in place of the queue)
+type Registerer interface {
+	Register(name string, queue goqueue.Length) (err error)
+	Instrument(name string, queue Instrumentable) (instrumented interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Length
+	}, err error)
+	Unregister(name string)
+}
+
+//Snapshotter can be used to get a snapshot of the metrics for all of the
+// registered queues by name
+type Snapshotter interface {
+	Snapshot() (metrics map[string]Metrics)
+}
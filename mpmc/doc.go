@@ -0,0 +1,9 @@
+// Copyright 2022 antonio-alexander. All rights reserved.
+// Use of this source code is governed by an MIT
+// license that can be found in the LICENSE file.
+
+/*
+	Package mpmc provides a bounded queue implementation for multiple producers
+	and multiple consumers where enqueue and dequeue are lock-free
+*/
+package mpmc
@@ -0,0 +1,171 @@
+package mpmc
+
+import (
+	"runtime"
+	"sync/atomic"
+
+	internal "github.com/antonio-alexander/go-queue/internal"
+)
+
+//minCells is the minimum number of cells in a ring, with a single cell the
+// sequence of a full cell (position + 1) would be the same as the sequence
+// of an empty cell for the next position, so a full queue would accept
+// another enqueue; a queue with a capacity of one has two cells and
+// enforces the capacity when enqueuing instead
+const minCells = 2
+
+//sealed is set on both positions of a ring while an exclusive operation is
+// in progress (and is never cleared once a ring has been replaced or the
+// queue closed), enqueues and dequeues that see it will wait for the
+// exclusive operation to complete rather than claim a position
+const sealed uint64 = 1 << 63
+
+//cacheLinePad is used to keep the enqueue and dequeue positions on
+// separate cache lines so producers and consumers don't invalidate
+// each other
+type cacheLinePad [56]byte
+
+//cell is a single slot in the queue, the sequence is used to determine
+// whether the slot is ready to be enqueued into (sequence == position)
+// or dequeued from (sequence == position + 1)
+type cell[T any] struct {
+	sequence uint64
+	item     T
+}
+
+//ring holds the cells, the positions and the signals of the queue such that
+// they can be replaced at once (e.g. on resize) without locking enqueues
+// and dequeues
+type ring[T any] struct {
+	//KIM: the positions must be first so they're 64-bit aligned for
+	// atomic operations on 32-bit platforms
+	enqueuePos uint64
+	_          cacheLinePad
+	dequeuePos uint64
+	_          cacheLinePad
+	signallers int32
+	closing    int32
+	start      uint64
+	size       int
+	cells      []cell[T]
+	signalIn   chan struct{}
+	signalOut  chan struct{}
+}
+
+//newRing can be used to create a ring of the given size with the given
+// items, the positions start at the number of cells rather than zero such
+// that EnqueueInFront() can move the dequeue position backwards without it
+// wrapping around; it expects that the items will fit
+func newRing[T any](size int, items []T, signalIn, signalOut chan struct{}) *ring[T] {
+	nCells := size
+	if nCells < minCells {
+		nCells = minCells
+	}
+	cells, position := make([]cell[T], nCells), uint64(nCells)
+	for i := range cells {
+		cells[i].sequence = position + uint64(i)
+	}
+	for i, item := range items {
+		cells[i].item, cells[i].sequence = item, position+uint64(i)+1
+	}
+	return &ring[T]{
+		enqueuePos: position + uint64(len(items)),
+		dequeuePos: position,
+		start:      position,
+		size:       size,
+		cells:      cells,
+		signalIn:   signalIn,
+		signalOut:  signalOut,
+	}
+}
+
+//positions will return the enqueue and dequeue positions without the
+// sealed bit, the dequeue position is loaded first so it can't be ahead
+// of the enqueue position
+func (r *ring[T]) positions() (enqueuePos, dequeuePos uint64) {
+	dequeuePos = atomic.LoadUint64(&r.dequeuePos) &^ sealed
+	enqueuePos = atomic.LoadUint64(&r.enqueuePos) &^ sealed
+	return
+}
+
+//length will return the number of items between the given positions
+func (r *ring[T]) length(enqueuePos, dequeuePos uint64) int {
+	size := int64(enqueuePos - dequeuePos)
+	switch {
+	case size < 0:
+		return 0
+	case size > int64(r.size):
+		return r.size
+	}
+	return int(size)
+}
+
+//seal will set the sealed bit on both positions such that no more
+// positions can be claimed and then wait for enqueues and dequeues that
+// have already claimed a position to finish with their cell; once sealed,
+// the ring can be safely modified
+func (r *ring[T]) seal() (enqueuePos, dequeuePos uint64) {
+	setSealed := func(position *uint64) uint64 {
+		for {
+			value := atomic.LoadUint64(position)
+			if value&sealed != 0 ||
+				atomic.CompareAndSwapUint64(position, value, value|sealed) {
+				return value &^ sealed
+			}
+		}
+	}
+	enqueuePos, dequeuePos = setSealed(&r.enqueuePos), setSealed(&r.dequeuePos)
+	capacity := uint64(len(r.cells))
+	for position := enqueuePos - capacity; position < enqueuePos; position++ {
+		//KIM: the cells between the positions should have been enqueued
+		// into, the remaining cells should have been dequeued from
+		expected := position + 1
+		if position < dequeuePos {
+			expected = position + capacity
+		}
+		for atomic.LoadUint64(&r.cells[position%capacity].sequence) != expected {
+			runtime.Gosched()
+		}
+	}
+	return
+}
+
+//unseal will store the given positions and clear the sealed bit such that
+// enqueues and dequeues can continue, it expects the ring to be sealed
+func (r *ring[T]) unseal(enqueuePos, dequeuePos uint64) {
+	atomic.StoreUint64(&r.dequeuePos, dequeuePos)
+	atomic.StoreUint64(&r.enqueuePos, enqueuePos)
+}
+
+//peekFromHead will copy up to n items from the given dequeue position, it
+// expects the ring to be sealed
+func (r *ring[T]) peekFromHead(enqueuePos, dequeuePos uint64, n int) (items []T) {
+	capacity := uint64(len(r.cells))
+	for position := dequeuePos; position < enqueuePos && len(items) < n; position++ {
+		items = append(items, r.cells[position%capacity].item)
+	}
+	return
+}
+
+//sendSignal will send the signal unless the ring's signals are being
+// closed, the signallers are counted such that closeSignals() can wait
+// for them
+func (r *ring[T]) sendSignal(stats *internal.Stats, signal chan struct{}) {
+	atomic.AddInt32(&r.signallers, 1)
+	if atomic.LoadInt32(&r.closing) == 0 {
+		stats.SendSignal(signal)
+	}
+	atomic.AddInt32(&r.signallers, -1)
+}
+
+//closeSignals will stop any new signals from being sent, wait for signals
+// that are being sent and then close the signals, it expects the ring to be
+// sealed
+func (r *ring[T]) closeSignals() {
+	atomic.StoreInt32(&r.closing, 1)
+	for atomic.LoadInt32(&r.signallers) > 0 {
+		runtime.Gosched()
+	}
+	close(r.signalIn)
+	close(r.signalOut)
+}
@@ -0,0 +1,615 @@
+package mpmc
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+	finite "github.com/antonio-alexander/go-queue/finite"
+	internal "github.com/antonio-alexander/go-queue/internal"
+)
+
+type queueMPMC[T any] struct {
+	//KIM: the high-water mark must be first so it's 64-bit aligned for
+	// atomic operations on 32-bit platforms
+	highWaterMark uint64
+	sync.Mutex
+	ring      atomic.Value
+	closed    int32
+	eventIn   int32
+	eventOut  int32
+	waiters   int32
+	waitMutex sync.Mutex
+	changed   internal.Notifier
+	enqueued  uint64
+	dequeued  uint64
+	stats     *internal.Stats
+}
+
+//New can be used to create a multi-producer, multi-consumer queue of empty
+// interface with the given size, if size is less than one, it will be one.
+// Enqueue and dequeue operations are lock-free, operations that need to see
+// the whole queue (e.g. Peek(), EnqueueInFront(), EnqueueLossy(), Resize()
+// and Close()) will stop enqueues and dequeues until they complete
+func New(size int) interface {
+	goqueue.Owner
+	goqueue.GarbageCollecter
+	goqueue.Dequeuer
+	goqueue.DequeuerCtx
+	goqueue.Enqueuer
+	goqueue.EnqueuerCtx
+	goqueue.EnqueueInFronter
+	goqueue.Length
+	goqueue.Event
+	goqueue.Peeker
+	goqueue.PeekerCtx
+	goqueue.Statser
+	finite.EnqueueLossy
+	finite.Resizer
+	finite.Capacity
+} {
+	return NewOf[interface{}](size)
+}
+
+//NewOf can be used to create a type-safe multi-producer, multi-consumer
+// queue of T with the given size, if size is less than one, it will be one
+func NewOf[T any](size int) interface {
+	goqueue.OwnerOf[T]
+	goqueue.GarbageCollecter
+	goqueue.DequeuerOf[T]
+	goqueue.DequeuerCtxOf[T]
+	goqueue.EnqueuerOf[T]
+	goqueue.EnqueuerCtxOf[T]
+	goqueue.EnqueueInFronterOf[T]
+	goqueue.Length
+	goqueue.Event
+	goqueue.PeekerOf[T]
+	goqueue.PeekerCtxOf[T]
+	goqueue.Statser
+	finite.EnqueueLossyOf[T]
+	finite.ResizerOf[T]
+	finite.Capacity
+} {
+	if size < 1 {
+		size = 1
+	}
+	q := &queueMPMC[T]{stats: new(internal.Stats)}
+	q.ring.Store(newRing[T](size, nil, make(chan struct{}, size), make(chan struct{}, size)))
+	return q
+}
+
+func (q *queueMPMC[T]) load() *ring[T] {
+	return q.ring.Load().(*ring[T])
+}
+
+func (q *queueMPMC[T]) isClosed() bool {
+	return atomic.LoadInt32(&q.closed) != 0
+}
+
+//watch will register the caller as waiting for the state of the queue to
+// change and return a channel that will be closed the next time it does,
+// unwatch() must be called once the caller is no longer waiting
+func (q *queueMPMC[T]) watch() <-chan struct{} {
+	atomic.AddInt32(&q.waiters, 1)
+	q.waitMutex.Lock()
+	defer q.waitMutex.Unlock()
+	return q.changed.Wait()
+}
+
+func (q *queueMPMC[T]) unwatch() {
+	atomic.AddInt32(&q.waiters, -1)
+}
+
+//notify will wake up anyone waiting for the state of the queue to change,
+// the notifier is only locked if there's someone waiting
+func (q *queueMPMC[T]) notify() {
+	if atomic.LoadInt32(&q.waiters) <= 0 {
+		return
+	}
+	q.waitMutex.Lock()
+	defer q.waitMutex.Unlock()
+	q.changed.Notify()
+}
+
+//try will call fn until it returns true, the context is done or the queue
+// is closed. The caller is registered as waiting before fn is called such
+// that a change that occurs after fn returns false can't be missed
+func (q *queueMPMC[T]) try(ctx context.Context, fn func() (done bool)) error {
+	for {
+		var done bool
+
+		changed := q.watch()
+		closed := q.isClosed()
+		if !closed {
+			done = fn()
+		}
+		switch {
+		case closed:
+			q.unwatch()
+			return goqueue.ErrQueueClosed
+		case done:
+			q.unwatch()
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			q.unwatch()
+			return ctx.Err()
+		case <-changed:
+			q.unwatch()
+		}
+	}
+}
+
+//wait is called by enqueues and dequeues that find the ring sealed, it
+// will wait for the exclusive operation to complete (it holds the lock)
+// and return true if the queue was closed
+func (q *queueMPMC[T]) wait() (closed bool) {
+	q.Lock()
+	defer q.Unlock()
+	return q.isClosed()
+}
+
+//exclusive will lock the queue and seal the ring such that no enqueues or
+// dequeues can occur while fn is called, the ring is unsealed with the
+// positions returned by fn; it will return false if the queue is closed
+func (q *queueMPMC[T]) exclusive(fn func(r *ring[T], enqueuePos, dequeuePos uint64) (uint64, uint64)) bool {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.isClosed() {
+		return false
+	}
+	r := q.load()
+	enqueuePos, dequeuePos := r.seal()
+	r.unseal(fn(r, enqueuePos, dequeuePos))
+
+	return true
+}
+
+//replace will replace the ring with one of the given size that holds the
+// items, it expects the queue to be locked and the ring to be sealed such
+// that the count of items enqueued/dequeued can be carried over
+func (q *queueMPMC[T]) replace(r *ring[T], enqueuePos, dequeuePos uint64, size int, items []T, signalIn, signalOut chan struct{}) {
+	q.enqueued += enqueuePos - r.start
+	q.dequeued += dequeuePos - r.start
+	q.ring.Store(newRing(size, items, signalIn, signalOut))
+}
+
+//mark will update the high-water mark if the given size is greater, it's
+// only written to if the size is greater so it's rarely contended
+func (q *queueMPMC[T]) mark(size uint64) {
+	for {
+		highWaterMark := atomic.LoadUint64(&q.highWaterMark)
+		if size <= highWaterMark ||
+			atomic.CompareAndSwapUint64(&q.highWaterMark, highWaterMark, size) {
+			return
+		}
+	}
+}
+
+//enqueue will claim the cell at the enqueue position (if it's empty) and
+// place the item in it without locking; if the ring is sealed, it will
+// wait for the exclusive operation to complete and try again
+func (q *queueMPMC[T]) enqueue(item T) (overflow bool) {
+	for {
+		r := q.load()
+		capacity := uint64(len(r.cells))
+		position := atomic.LoadUint64(&r.enqueuePos)
+		for position&sealed == 0 {
+			c := &r.cells[position%capacity]
+			sequence := atomic.LoadUint64(&c.sequence)
+			switch difference := int64(sequence - position); {
+			case difference == 0:
+				//KIM: if the ring has more cells than its size, the cell
+				// being empty doesn't mean there's room; the dequeue position
+				// only moves forward, so if there's room now there will be
+				// room once the position is claimed (if the dequeue position
+				// is ahead, the position is out of date)
+				if r.size < len(r.cells) {
+					size := int64(position - atomic.LoadUint64(&r.dequeuePos)&^sealed)
+					if size < 0 {
+						position = atomic.LoadUint64(&r.enqueuePos)
+						continue
+					}
+					if size >= int64(r.size) {
+						return true
+					}
+				}
+				if !atomic.CompareAndSwapUint64(&r.enqueuePos, position, position+1) {
+					position = atomic.LoadUint64(&r.enqueuePos)
+					continue
+				}
+				c.item = item
+				atomic.StoreUint64(&c.sequence, position+1)
+				if size := int64(position + 1 - atomic.LoadUint64(&r.dequeuePos)&^sealed); size > 0 {
+					q.mark(uint64(size))
+				}
+				if atomic.LoadInt32(&q.eventIn) != 0 {
+					r.sendSignal(q.stats, r.signalIn)
+				}
+				q.notify()
+				return false
+			case difference < 0:
+				//KIM: the cell still holds an item from the previous lap
+				// so the queue is full
+				return true
+			default:
+				position = atomic.LoadUint64(&r.enqueuePos)
+			}
+		}
+		if q.wait() {
+			return true
+		}
+	}
+}
+
+func (q *queueMPMC[T]) enqueueMultiple(items []T) (remainingElements []T, overflow bool) {
+	for i, item := range items {
+		if overflow = q.enqueue(item); overflow {
+			return items[i:], true
+		}
+	}
+	return
+}
+
+//dequeue will claim the cell at the dequeue position (if it's full) and
+// remove the item from it without locking; if the ring is sealed, it will
+// wait for the exclusive operation to complete and try again
+func (q *queueMPMC[T]) dequeue() (item T, underflow bool) {
+	var zero T
+
+	for {
+		r := q.load()
+		capacity := uint64(len(r.cells))
+		position := atomic.LoadUint64(&r.dequeuePos)
+		for position&sealed == 0 {
+			c := &r.cells[position%capacity]
+			sequence := atomic.LoadUint64(&c.sequence)
+			switch difference := int64(sequence - (position + 1)); {
+			case difference == 0:
+				if !atomic.CompareAndSwapUint64(&r.dequeuePos, position, position+1) {
+					position = atomic.LoadUint64(&r.dequeuePos)
+					continue
+				}
+				item, c.item = c.item, zero
+				atomic.StoreUint64(&c.sequence, position+capacity)
+				if atomic.LoadInt32(&q.eventOut) != 0 {
+					r.sendSignal(q.stats, r.signalOut)
+				}
+				q.notify()
+				return item, false
+			case difference < 0:
+				//KIM: the cell hasn't been enqueued into yet so the
+				// queue is empty
+				return zero, true
+			default:
+				position = atomic.LoadUint64(&r.dequeuePos)
+			}
+		}
+		if q.wait() {
+			return zero, true
+		}
+	}
+}
+
+func (q *queueMPMC[T]) dequeueMultiple(n int) (items []T) {
+	for len(items) < n {
+		item, underflow := q.dequeue()
+		if underflow {
+			break
+		}
+		items = append(items, item)
+	}
+	return
+}
+
+//peekFromHead will copy up to n items from the head of the queue, enqueues
+// and dequeues will wait until it's complete
+func (q *queueMPMC[T]) peekFromHead(n int) (items []T) {
+	q.exclusive(func(r *ring[T], enqueuePos, dequeuePos uint64) (uint64, uint64) {
+		items = r.peekFromHead(enqueuePos, dequeuePos, n)
+		return enqueuePos, dequeuePos
+	})
+	return
+}
+
+//getSignal will return the signal and enable it if it isn't enabled; when
+// the signal is enabled, it's sent n times such that anyone that waits on
+// the signal won't miss the changes that occured before it was enabled
+func (q *queueMPMC[T]) getSignal(enabled *int32, signal func(r *ring[T]) chan struct{}, n func(r *ring[T], size int) int) <-chan struct{} {
+	q.Lock()
+	defer q.Unlock()
+
+	r := q.load()
+	if q.isClosed() || !atomic.CompareAndSwapInt32(enabled, 0, 1) {
+		return signal(r)
+	}
+	for i := n(r, r.length(r.positions())); i > 0; i-- {
+		internal.SendSignal(signal(r))
+	}
+	return signal(r)
+}
+
+func (q *queueMPMC[T]) Close() (remainingElements []T) {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.isClosed() {
+		return
+	}
+	r := q.load()
+	enqueuePos, dequeuePos := r.seal()
+	remainingElements = r.peekFromHead(enqueuePos, dequeuePos, r.size)
+	r.closeSignals()
+	q.replace(r, enqueuePos, dequeuePos, r.size, nil, r.signalIn, r.signalOut)
+	q.load().seal()
+	atomic.StoreInt32(&q.closed, 1)
+	q.waitMutex.Lock()
+	q.changed.Notify()
+	q.waitMutex.Unlock()
+
+	return
+}
+
+func (q *queueMPMC[T]) GarbageCollect() {
+	//KIM: items are zeroed as they're dequeued, so there's nothing to
+	// collect, but enqueues and dequeues are stopped for consistency
+	// with the finite queue
+	if q.exclusive(func(r *ring[T], enqueuePos, dequeuePos uint64) (uint64, uint64) {
+		return enqueuePos, dequeuePos
+	}) {
+		q.stats.GarbageCollect()
+	}
+}
+
+func (q *queueMPMC[T]) Resize(newSize int) (items []T) {
+	q.Lock()
+	defer q.Unlock()
+
+	//ensure that no operations occur if the size hasn't changed,
+	// if there's a need to remove items, remove them from the front
+	// then re-create the ring and signal channels
+	r := q.load()
+	if q.isClosed() || newSize == r.size {
+		return
+	}
+	if newSize < 1 {
+		newSize = 1
+	}
+	enqueuePos, dequeuePos := r.seal()
+	remaining := r.peekFromHead(enqueuePos, dequeuePos, r.size)
+	if len(remaining) > newSize {
+		items, remaining = remaining[:len(remaining)-newSize], remaining[len(remaining)-newSize:]
+	}
+	r.closeSignals()
+	q.replace(r, enqueuePos, dequeuePos, newSize, remaining,
+		make(chan struct{}, newSize), make(chan struct{}, newSize))
+	q.stats.Resize()
+	q.notify()
+
+	return
+}
+
+func (q *queueMPMC[T]) GetSignalIn() (signal <-chan struct{}) {
+	return q.getSignal(&q.eventIn, func(r *ring[T]) chan struct{} {
+		return r.signalIn
+	}, func(r *ring[T], size int) int {
+		return size
+	})
+}
+
+func (q *queueMPMC[T]) GetSignalOut() (signal <-chan struct{}) {
+	return q.getSignal(&q.eventOut, func(r *ring[T]) chan struct{} {
+		return r.signalOut
+	}, func(r *ring[T], size int) int {
+		return r.size - size
+	})
+}
+
+func (q *queueMPMC[T]) Dequeue() (item T, underflow bool) {
+	if item, underflow = q.dequeue(); underflow {
+		q.stats.Underflow()
+	}
+	return
+}
+
+func (q *queueMPMC[T]) DequeueCtx(ctx context.Context) (item T, err error) {
+	err = q.try(ctx, func() bool {
+		var underflow bool
+
+		item, underflow = q.dequeue()
+		return !underflow
+	})
+	return
+}
+
+func (q *queueMPMC[T]) DequeueMultiple(n int) (items []T) {
+	if items = q.dequeueMultiple(n); len(items) <= 0 {
+		q.stats.Underflow()
+	}
+	return
+}
+
+func (q *queueMPMC[T]) DequeueMultipleCtx(ctx context.Context, n int) (items []T, err error) {
+	err = q.try(ctx, func() bool {
+		items = append(items, q.dequeueMultiple(n-len(items))...)
+		return len(items) >= n
+	})
+	return
+}
+
+func (q *queueMPMC[T]) Flush() (items []T) {
+	//KIM: flush is limited to the capacity, otherwise it may never
+	// return if producers are enqueuing as fast as it's dequeuing
+	if items = q.dequeueMultiple(q.Capacity()); len(items) <= 0 {
+		q.stats.Underflow()
+	}
+	return
+}
+
+func (q *queueMPMC[T]) Enqueue(item T) (overflow bool) {
+	if overflow = q.enqueue(item); overflow {
+		q.stats.Overflow()
+	}
+	return
+}
+
+func (q *queueMPMC[T]) EnqueueCtx(ctx context.Context, item T) (err error) {
+	return q.try(ctx, func() bool {
+		return !q.enqueue(item)
+	})
+}
+
+func (q *queueMPMC[T]) EnqueueMultiple(items []T) (remainingElements []T, overflow bool) {
+	if remainingElements, overflow = q.enqueueMultiple(items); overflow {
+		q.stats.Overflow()
+	}
+	return
+}
+
+func (q *queueMPMC[T]) EnqueueMultipleCtx(ctx context.Context, items []T) (remainingElements []T, err error) {
+	remainingElements = items
+	err = q.try(ctx, func() bool {
+		var overflow bool
+
+		remainingElements, overflow = q.enqueueMultiple(remainingElements)
+		return !overflow
+	})
+	return
+}
+
+func (q *queueMPMC[T]) EnqueueLossy(item T) (discardedElement T, discard bool) {
+	var zero T
+
+	if !q.exclusive(func(r *ring[T], enqueuePos, dequeuePos uint64) (uint64, uint64) {
+		capacity := uint64(len(r.cells))
+		if r.length(enqueuePos, dequeuePos) >= r.size {
+			c := &r.cells[dequeuePos%capacity]
+			discardedElement, discard = c.item, true
+			c.item, c.sequence = zero, dequeuePos+capacity
+			dequeuePos++
+			q.stats.LossyDiscard()
+			if atomic.LoadInt32(&q.eventOut) != 0 {
+				q.stats.SendSignal(r.signalOut)
+			}
+		}
+		c := &r.cells[enqueuePos%capacity]
+		c.item, c.sequence = item, enqueuePos+1
+		enqueuePos++
+		q.mark(uint64(r.length(enqueuePos, dequeuePos)))
+		if atomic.LoadInt32(&q.eventIn) != 0 {
+			q.stats.SendSignal(r.signalIn)
+		}
+		return enqueuePos, dequeuePos
+	}) {
+		//KIM: once closed, the item can't be enqueued and nothing is
+		// actually discarded
+		q.stats.Overflow()
+		return item, true
+	}
+	q.notify()
+
+	return
+}
+
+func (q *queueMPMC[T]) EnqueueInFront(item T) (overflow bool) {
+	if !q.exclusive(func(r *ring[T], enqueuePos, dequeuePos uint64) (uint64, uint64) {
+		if overflow = r.length(enqueuePos, dequeuePos) >= r.size; overflow {
+			return enqueuePos, dequeuePos
+		}
+
+		//KIM: the cell in front of the dequeue position is empty (it's
+		// waiting for the enqueue position to lap around to it), so it can
+		// be filled and the dequeue position moved back to it; moving the
+		// dequeue position back would un-count a dequeue, so the counts
+		// are adjusted
+		dequeuePos--
+		c := &r.cells[dequeuePos%uint64(len(r.cells))]
+		c.item, c.sequence = item, dequeuePos+1
+		q.enqueued++
+		q.dequeued++
+		q.mark(uint64(r.length(enqueuePos, dequeuePos)))
+		if atomic.LoadInt32(&q.eventIn) != 0 {
+			q.stats.SendSignal(r.signalIn)
+		}
+		return enqueuePos, dequeuePos
+	}) {
+		overflow = true
+	}
+	if overflow {
+		q.stats.Overflow()
+		return
+	}
+	q.notify()
+
+	return
+}
+
+func (q *queueMPMC[T]) Length() (size int) {
+	r := q.load()
+	return r.length(r.positions())
+}
+
+func (q *queueMPMC[T]) Capacity() (capacity int) {
+	return q.load().size
+}
+
+func (q *queueMPMC[T]) Peek() (items []T) {
+	return q.peekFromHead(q.Capacity())
+}
+
+func (q *queueMPMC[T]) PeekHead() (item T, underflow bool) {
+	items := q.peekFromHead(1)
+	if len(items) <= 0 {
+		return item, true
+	}
+	return items[0], false
+}
+
+func (q *queueMPMC[T]) PeekHeadCtx(ctx context.Context) (item T, err error) {
+	err = q.try(ctx, func() bool {
+		items := q.peekFromHead(1)
+		if len(items) <= 0 {
+			return false
+		}
+		item = items[0]
+		return true
+	})
+	return
+}
+
+func (q *queueMPMC[T]) PeekFromHead(n int) (items []T) {
+	return q.peekFromHead(n)
+}
+
+func (q *queueMPMC[T]) PeekFromHeadCtx(ctx context.Context, n int) (items []T, err error) {
+	err = q.try(ctx, func() bool {
+		//KIM: if n is greater than the capacity, we can only wait
+		// until the queue is full
+		items = q.peekFromHead(n)
+		return len(items) >= n || len(items) >= q.Capacity()
+	})
+	if err == goqueue.ErrQueueClosed {
+		items = nil
+	}
+	return
+}
+
+//Stats will return a snapshot of the stats, the number of items enqueued
+// and dequeued are calculated from the positions (rather than counted) so
+// that enqueues and dequeues don't contend on a shared counter
+func (q *queueMPMC[T]) Stats() (stats goqueue.Stats) {
+	q.Lock()
+	defer q.Unlock()
+
+	r := q.load()
+	enqueuePos, dequeuePos := r.positions()
+	stats = q.stats.Snapshot()
+	stats.Enqueued = q.enqueued + enqueuePos - r.start
+	stats.Dequeued = q.dequeued + dequeuePos - r.start
+	stats.HighWaterMark = atomic.LoadUint64(&q.highWaterMark)
+
+	return
+}
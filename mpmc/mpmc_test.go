@@ -0,0 +1,464 @@
+package mpmc_test
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+	finite "github.com/antonio-alexander/go-queue/finite"
+	finite_tests "github.com/antonio-alexander/go-queue/finite/tests"
+	mpmc "github.com/antonio-alexander/go-queue/mpmc"
+	goqueue_tests "github.com/antonio-alexander/go-queue/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	mustTimeout = time.Second
+	mustRate    = time.Millisecond
+	casef       = "case: %s"
+)
+
+func init() {
+	rand.Seed(int64(time.Now().Nanosecond()))
+}
+
+func TestMPMCQueue(t *testing.T) {
+	t.Run("Test Enqueue", finite_tests.TestEnqueue(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return mpmc.New(size)
+	}))
+	t.Run("Test Enqueue Multiple", finite_tests.TestEnqueueMultiple(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+	} {
+		return mpmc.New(size)
+	}))
+	t.Run("Test Enqueue Event", finite_tests.TestEnqueueEvent(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Event
+	} {
+		return mpmc.New(size)
+	}))
+	t.Run("Test Resize", finite_tests.TestResize(t, func(size int) interface {
+		finite.Capacity
+		goqueue.Enqueuer
+		goqueue.Owner
+		finite.Resizer
+	} {
+		return mpmc.New(size)
+	}))
+	t.Run("Test Enqueue Lossy", finite_tests.TestEnqueueLossy(t, func(size int) interface {
+		goqueue.Owner
+		finite.EnqueueLossy
+	} {
+		return mpmc.New(size)
+	}))
+	t.Run("Test Enqueue In Front", finite_tests.TestEnqueueInFront(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.EnqueueInFronter
+		goqueue.Peeker
+	} {
+		return mpmc.New(size)
+	}))
+	t.Run("Test Enqueue Ctx", finite_tests.TestEnqueueCtx(t, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Dequeuer
+		goqueue.EnqueuerCtx
+	} {
+		return mpmc.New(size)
+	}))
+	t.Run("Test Capacity", finite_tests.TestCapacity(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		finite.Capacity
+	} {
+		return mpmc.New(size)
+	}))
+	t.Run("Test Stats", finite_tests.TestStats(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.EnqueueInFronter
+		goqueue.Statser
+		finite.EnqueueLossy
+		finite.Resizer
+	} {
+		return mpmc.New(size)
+	}))
+	t.Run("Test Capacity One", func(t *testing.T) {
+		cases := map[string]struct {
+			iSize int
+		}{
+			"zero": {iSize: 0},
+			"one":  {iSize: 1},
+		}
+		for cDesc, c := range cases {
+			//KIM: a queue with a capacity of one has more cells than its
+			// capacity, confirm that it still overflows once full
+			q := mpmc.New(c.iSize)
+			assert.Equal(t, 1, q.Capacity(), casef, cDesc)
+			for i := 0; i < 3; i++ {
+				assert.False(t, q.Enqueue(i), casef, cDesc)
+				assert.True(t, q.Enqueue(-1), casef, cDesc)
+				assert.True(t, q.EnqueueInFront(-1), casef, cDesc)
+				assert.Equal(t, 1, q.Length(), casef, cDesc)
+				item, underflow := q.Dequeue()
+				assert.False(t, underflow, casef, cDesc)
+				assert.Equal(t, i, item, casef, cDesc)
+				_, underflow = q.Dequeue()
+				assert.True(t, underflow, casef, cDesc)
+			}
+			assert.False(t, q.EnqueueInFront(0), casef, cDesc)
+			item, discard := q.EnqueueLossy(1)
+			assert.True(t, discard, casef, cDesc)
+			assert.Equal(t, 0, item, casef, cDesc)
+			assert.Equal(t, []interface{}{1}, q.Peek(), casef, cDesc)
+			assert.Empty(t, q.Resize(2), casef, cDesc)
+			assert.False(t, q.Enqueue(2), casef, cDesc)
+			assert.Equal(t, []interface{}{1}, q.Resize(1), casef, cDesc)
+			assert.True(t, q.Enqueue(3), casef, cDesc)
+			assert.Equal(t, []interface{}{2}, q.Close(), casef, cDesc)
+		}
+	})
+	t.Run("Test Producers Consumers", func(t *testing.T) {
+		const nProducers, nConsumers, nItems, size = 4, 4, 1000, 8
+
+		type item struct{ producer, sequence int }
+
+		var wg sync.WaitGroup
+
+		//KIM: the queue is much smaller than the number of items so the
+		// positions will lap the cells many times; the items from each
+		// producer must be received in order by each consumer
+		q := mpmc.NewOf[item](size)
+		defer q.Close()
+		received := make(chan []item, nConsumers)
+		for i := 0; i < nProducers; i++ {
+			wg.Add(1)
+			go func(producer int) {
+				defer wg.Done()
+				for sequence := 0; sequence < nItems; {
+					if overflow := q.Enqueue(item{producer, sequence}); overflow {
+						runtime.Gosched()
+						continue
+					}
+					sequence++
+				}
+			}(i)
+		}
+		stopper := make(chan struct{})
+		for i := 0; i < nConsumers; i++ {
+			go func() {
+				var items []item
+
+				defer func() { received <- items }()
+				for {
+					item, underflow := q.Dequeue()
+					if !underflow {
+						items = append(items, item)
+						continue
+					}
+					select {
+					case <-stopper:
+						items = append(items, q.Flush()...)
+						return
+					default:
+						runtime.Gosched()
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		close(stopper)
+		counts := make(map[int]int)
+		for i := 0; i < nConsumers; i++ {
+			last := make(map[int]int)
+			for _, item := range <-received {
+				if sequence, ok := last[item.producer]; ok {
+					assert.Greater(t, item.sequence, sequence)
+				}
+				last[item.producer] = item.sequence
+				counts[item.producer]++
+			}
+		}
+		for i := 0; i < nProducers; i++ {
+			assert.Equal(t, nItems, counts[i])
+		}
+		assert.Zero(t, q.Length())
+	})
+	t.Run("Test Exclusive Operations", func(t *testing.T) {
+		const nProducers, nConsumers, nItems, size = 4, 4, 1000, 8
+
+		var wg sync.WaitGroup
+		var mutex sync.Mutex
+		var dropped []int
+
+		//KIM: exclusive operations stop enqueues and dequeues by sealing
+		// the positions, resize and peek while producers and consumers are
+		// active and confirm that every item is received (or dropped by a
+		// resize) exactly once
+		q := mpmc.NewOf[int](size)
+		defer q.Close()
+		received := make(chan []int, nConsumers)
+		for i := 0; i < nProducers; i++ {
+			wg.Add(1)
+			go func(producer int) {
+				defer wg.Done()
+				for i := 0; i < nItems; {
+					if overflow := q.Enqueue(producer*nItems + i); overflow {
+						runtime.Gosched()
+						continue
+					}
+					i++
+				}
+			}(i)
+		}
+		stopper := make(chan struct{})
+		exclusiveStopped := make(chan struct{})
+		go func() {
+			defer close(exclusiveStopped)
+			for i := 0; ; i++ {
+				select {
+				case <-stopper:
+					return
+				default:
+				}
+				items := q.Resize(size + i%2*size)
+				mutex.Lock()
+				dropped = append(dropped, items...)
+				mutex.Unlock()
+				q.Peek()
+				q.GarbageCollect()
+				runtime.Gosched()
+			}
+		}()
+		for i := 0; i < nConsumers; i++ {
+			go func() {
+				var items []int
+
+				defer func() { received <- items }()
+				for {
+					item, underflow := q.Dequeue()
+					if !underflow {
+						items = append(items, item)
+						continue
+					}
+					select {
+					case <-stopper:
+						<-exclusiveStopped
+						items = append(items, q.Flush()...)
+						return
+					default:
+						runtime.Gosched()
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		close(stopper)
+		counts := make(map[int]int)
+		for i := 0; i < nConsumers; i++ {
+			for _, item := range <-received {
+				counts[item]++
+			}
+		}
+		mutex.Lock()
+		for _, item := range dropped {
+			counts[item]++
+		}
+		mutex.Unlock()
+		assert.Len(t, counts, nProducers*nItems)
+		for item, count := range counts {
+			assert.Equal(t, 1, count, "item: %d", item)
+		}
+		assert.Zero(t, q.Length())
+	})
+}
+
+func TestQueue(t *testing.T) {
+	t.Run("Test Dequeue", goqueue_tests.TestDequeue(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return mpmc.New(size)
+	}))
+	t.Run("Test Dequeue Event", goqueue_tests.TestDequeueEvent(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Dequeuer
+		goqueue.Enqueuer
+		goqueue.Event
+		goqueue.Owner
+	} {
+		return mpmc.New(size)
+	}))
+	t.Run("Test Dequeue Multiple", goqueue_tests.TestDequeueMultiple(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return mpmc.New(size)
+	}))
+	t.Run("Test Flush", goqueue_tests.TestFlush(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return mpmc.New(size)
+	}))
+	t.Run("Test Peek", goqueue_tests.TestPeek(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Peeker
+	} {
+		return mpmc.New(size)
+	}))
+	t.Run("Test Peek From Head", goqueue_tests.TestPeekFromHead(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Peeker
+	} {
+		return mpmc.New(size)
+	}))
+	t.Run("Test Dequeue Ctx", goqueue_tests.TestDequeueCtx(t, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.DequeuerCtx
+	} {
+		return mpmc.New(size)
+	}))
+	t.Run("Test Peek Ctx", goqueue_tests.TestPeekCtx(t, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Length
+		goqueue.PeekerCtx
+	} {
+		return mpmc.New(size)
+	}))
+	t.Run("Test Length", goqueue_tests.TestLength(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Length
+	} {
+		return mpmc.New(size)
+	}))
+	t.Run("Test Garbage Collect", goqueue_tests.TestGarbageCollect(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.GarbageCollecter
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return mpmc.New(size)
+	}))
+	t.Run("Test Queue", goqueue_tests.TestQueue(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return mpmc.New(size)
+	}))
+	t.Run("Test Asynchronous", goqueue_tests.TestAsync(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return mpmc.New(size)
+	}))
+	t.Run("Test Stats", goqueue_tests.TestStats(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Statser
+	} {
+		return mpmc.New(size)
+	}))
+}
+
+func TestMPMCQueueOf(t *testing.T) {
+	t.Run("Test Queue Of", goqueue_tests.TestQueueOf(t, func(size int) interface {
+		goqueue.OwnerOf[*goqueue.Example]
+		goqueue.EnqueuerOf[*goqueue.Example]
+		goqueue.DequeuerOf[*goqueue.Example]
+		goqueue.PeekerOf[*goqueue.Example]
+	} {
+		return mpmc.NewOf[*goqueue.Example](size)
+	}))
+	t.Run("Test Enqueue Lossy Of", finite_tests.TestEnqueueLossyOf(t, func(size int) interface {
+		goqueue.OwnerOf[*goqueue.Example]
+		goqueue.PeekerOf[*goqueue.Example]
+		finite.EnqueueLossyOf[*goqueue.Example]
+	} {
+		return mpmc.NewOf[*goqueue.Example](size)
+	}))
+	//KIM: a queue of empty interface satisfies the non-generic interfaces
+	// so the existing test suites can be used as-is
+	t.Run("Test Queue", goqueue_tests.TestQueue(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return mpmc.NewOf[interface{}](size)
+	}))
+	t.Run("Test Enqueue Lossy", finite_tests.TestEnqueueLossy(t, func(size int) interface {
+		goqueue.Owner
+		finite.EnqueueLossy
+	} {
+		return mpmc.NewOf[interface{}](size)
+	}))
+}
+
+// benchmarkGoroutines will measure the time it takes for the given number
+// of goroutines to each enqueue and then dequeue an item b.N times in total
+func benchmarkGoroutines(b *testing.B, nGoroutines int, q interface {
+	goqueue.EnqueuerOf[int]
+	goqueue.DequeuerOf[int]
+}) {
+	var wg sync.WaitGroup
+
+	b.ResetTimer()
+	for i := 0; i < nGoroutines; i++ {
+		n := b.N / nGoroutines
+		if i < b.N%nGoroutines {
+			n++
+		}
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				for q.Enqueue(i) {
+					runtime.Gosched()
+				}
+				for _, underflow := q.Dequeue(); underflow; _, underflow = q.Dequeue() {
+					runtime.Gosched()
+				}
+			}
+		}(n)
+	}
+	wg.Wait()
+}
+
+func BenchmarkGoroutines(b *testing.B) {
+	const size = 1024
+
+	for _, nGoroutines := range []int{1, 4, 16, 64} {
+		b.Run(fmt.Sprintf("mpmc_%d", nGoroutines), func(b *testing.B) {
+			benchmarkGoroutines(b, nGoroutines, mpmc.NewOf[int](size))
+		})
+		b.Run(fmt.Sprintf("finite_%d", nGoroutines), func(b *testing.B) {
+			benchmarkGoroutines(b, nGoroutines, finite.NewOf[int](size))
+		})
+	}
+}
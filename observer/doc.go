@@ -0,0 +1,10 @@
+// Copyright 2022 antonio-alexander. All rights reserved.
+// Use of this source code is governed by an MIT
+// license that can be found in the LICENSE file.
+
+/*
+	Package observer provides a decorator that can wrap any queue and call an
+	Observer after every operation with the result and latency of that
+	operation (e.g. for tracing or auditing)
+*/
+package observer
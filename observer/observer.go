@@ -0,0 +1,714 @@
+package observer
+
+import (
+	"context"
+	"time"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+	finite "github.com/antonio-alexander/go-queue/finite"
+)
+
+type queueObserved struct {
+	queue    interface{}
+	observer Observer
+}
+
+//observed describes the operations every wrapper implements
+type observed interface {
+	goqueue.Owner
+	goqueue.Dequeuer
+	goqueue.Enqueuer
+	goqueue.Length
+	goqueue.Event
+	goqueue.Peeker
+}
+
+//ctx describes the context-aware operations implemented by both the
+// finite and infinite queues
+type ctx interface {
+	goqueue.DequeuerCtx
+	goqueue.EnqueuerCtx
+	goqueue.PeekerCtx
+}
+
+//extended describes the operations (other than the context-aware ones)
+// implemented by both the finite and infinite queues
+type extended interface {
+	goqueue.DequeueFromBacker
+	goqueue.PeekFromTailer
+	goqueue.ConditionalDequeuer
+	goqueue.Remover
+}
+
+//lossy describes the operations specific to the finite queue
+type lossy interface {
+	finite.EnqueueLossy
+	finite.Resizer
+	finite.Capacity
+}
+
+//These are the wrappers that can be returned by New(), each of them only
+// exposes the operations of the interfaces it embeds (even though the
+// queueObserved implements all of them) so that a type assertion on the
+// wrapper will only succeed if the wrapped queue supports the operation;
+// each wrapper has a version that also exposes Statser
+type (
+	queueObservedBase struct {
+		observed
+	}
+
+	queueObservedGC struct {
+		observed
+		goqueue.GarbageCollecter
+	}
+
+	queueObservedInFront struct {
+		observed
+		goqueue.EnqueueInFronter
+	}
+
+	queueObservedGCInFront struct {
+		observed
+		goqueue.GarbageCollecter
+		goqueue.EnqueueInFronter
+	}
+
+	queueObservedCtx struct {
+		queueObservedGCInFront
+		ctx
+	}
+
+	queueObservedCtxLossy struct {
+		queueObservedCtx
+		lossy
+	}
+
+	queueObservedExtended struct {
+		queueObservedCtx
+		extended
+	}
+
+	queueObservedLossy struct {
+		queueObservedExtended
+		lossy
+	}
+
+	queueObservedBaseStats struct {
+		queueObservedBase
+		goqueue.Statser
+	}
+
+	queueObservedGCStats struct {
+		queueObservedGC
+		goqueue.Statser
+	}
+
+	queueObservedInFrontStats struct {
+		queueObservedInFront
+		goqueue.Statser
+	}
+
+	queueObservedGCInFrontStats struct {
+		queueObservedGCInFront
+		goqueue.Statser
+	}
+
+	queueObservedCtxStats struct {
+		queueObservedCtx
+		goqueue.Statser
+	}
+
+	queueObservedCtxLossyStats struct {
+		queueObservedCtxLossy
+		goqueue.Statser
+	}
+
+	queueObservedExtendedStats struct {
+		queueObservedExtended
+		goqueue.Statser
+	}
+
+	queueObservedLossyStats struct {
+		queueObservedLossy
+		goqueue.Statser
+	}
+)
+
+//New can be used to wrap any queue such that the observer is called after
+// every operation. The wrapper always implements Owner, Dequeuer, Enqueuer,
+// Length, Event and Peeker (if the queue doesn't implement one of them,
+// those operations aren't observed and behave as if the queue is empty and
+// full). The remaining interfaces (e.g. EnqueueInFronter, DequeuerCtx or
+// finite.EnqueueLossy) are only implemented by the wrapper if the queue
+// implements them, so they can be checked for with a type assertion; the
+// interfaces are grouped the same way as the queues in this module: the
+// context-aware interfaces are only implemented if the queue also implements
+// GarbageCollecter and EnqueueInFronter, while DequeueFromBacker,
+// PeekFromTailer, ConditionalDequeuer and Remover (infinite) and EnqueueLossy,
+// Resizer and Capacity (finite) are only implemented if the queue also
+// implements the context-aware interfaces. Statser is implemented if the
+// queue implements it regardless of the other interfaces
+func New(queue interface{}, observer Observer) interface {
+	goqueue.Owner
+	goqueue.Dequeuer
+	goqueue.Enqueuer
+	goqueue.Length
+	goqueue.Event
+	goqueue.Peeker
+} {
+	q := &queueObserved{
+		queue:    queue,
+		observer: observer,
+	}
+	_, isGC := queue.(goqueue.GarbageCollecter)
+	_, isInFront := queue.(goqueue.EnqueueInFronter)
+	_, isCtx := queue.(ctx)
+	_, isExtended := queue.(extended)
+	_, isLossy := queue.(lossy)
+	_, isStats := queue.(goqueue.Statser)
+	gcInFront := queueObservedGCInFront{q, q, q}
+	ctx := queueObservedCtx{gcInFront, q}
+	switch {
+	case isGC && isInFront && isCtx && isExtended && isLossy:
+		lossy := queueObservedLossy{queueObservedExtended{ctx, q}, q}
+		if isStats {
+			return &queueObservedLossyStats{lossy, q}
+		}
+		return &lossy
+	case isGC && isInFront && isCtx && isExtended:
+		extended := queueObservedExtended{ctx, q}
+		if isStats {
+			return &queueObservedExtendedStats{extended, q}
+		}
+		return &extended
+	case isGC && isInFront && isCtx && isLossy:
+		ctxLossy := queueObservedCtxLossy{ctx, q}
+		if isStats {
+			return &queueObservedCtxLossyStats{ctxLossy, q}
+		}
+		return &ctxLossy
+	case isGC && isInFront && isCtx:
+		if isStats {
+			return &queueObservedCtxStats{ctx, q}
+		}
+		return &ctx
+	case isGC && isInFront:
+		if isStats {
+			return &queueObservedGCInFrontStats{gcInFront, q}
+		}
+		return &gcInFront
+	case isGC:
+		gc := queueObservedGC{q, q}
+		if isStats {
+			return &queueObservedGCStats{gc, q}
+		}
+		return &gc
+	case isInFront:
+		inFront := queueObservedInFront{q, q}
+		if isStats {
+			return &queueObservedInFrontStats{inFront, q}
+		}
+		return &inFront
+	}
+	base := queueObservedBase{q}
+	if isStats {
+		return &queueObservedBaseStats{base, q}
+	}
+	return &base
+}
+
+func (q *queueObserved) observe(start time.Time, observation Observation) {
+	observation.Latency = time.Since(start)
+	q.observer.Observe(observation)
+}
+
+func (q *queueObserved) Close() (items []interface{}) {
+	owner, ok := q.queue.(goqueue.Owner)
+	if !ok {
+		return
+	}
+	start := time.Now()
+	items = owner.Close()
+	q.observe(start, Observation{
+		Operation: OperationClose,
+		Count:     len(items),
+	})
+	return
+}
+
+func (q *queueObserved) GarbageCollect() {
+	gc, ok := q.queue.(goqueue.GarbageCollecter)
+	if !ok {
+		return
+	}
+	start := time.Now()
+	gc.GarbageCollect()
+	q.observe(start, Observation{
+		Operation: OperationGarbageCollect,
+	})
+}
+
+func (q *queueObserved) Dequeue() (item interface{}, underflow bool) {
+	dequeuer, ok := q.queue.(goqueue.Dequeuer)
+	if !ok {
+		return nil, true
+	}
+	start := time.Now()
+	item, underflow = dequeuer.Dequeue()
+	observation := Observation{
+		Operation: OperationDequeue,
+		Underflow: underflow,
+	}
+	if !underflow {
+		observation.Count = 1
+	}
+	q.observe(start, observation)
+	return
+}
+
+func (q *queueObserved) DequeueMultiple(n int) (items []interface{}) {
+	dequeuer, ok := q.queue.(goqueue.Dequeuer)
+	if !ok {
+		return
+	}
+	start := time.Now()
+	items = dequeuer.DequeueMultiple(n)
+	q.observe(start, Observation{
+		Operation: OperationDequeueMultiple,
+		Count:     len(items),
+		Underflow: len(items) <= 0,
+	})
+	return
+}
+
+func (q *queueObserved) Flush() (items []interface{}) {
+	dequeuer, ok := q.queue.(goqueue.Dequeuer)
+	if !ok {
+		return
+	}
+	start := time.Now()
+	items = dequeuer.Flush()
+	q.observe(start, Observation{
+		Operation: OperationFlush,
+		Count:     len(items),
+		Underflow: len(items) <= 0,
+	})
+	return
+}
+
+func (q *queueObserved) DequeueCtx(ctx context.Context) (item interface{}, err error) {
+	dequeuer, ok := q.queue.(goqueue.DequeuerCtx)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	start := time.Now()
+	item, err = dequeuer.DequeueCtx(ctx)
+	observation := Observation{
+		Operation: OperationDequeueCtx,
+		Err:       err,
+	}
+	if err == nil {
+		observation.Count = 1
+	}
+	q.observe(start, observation)
+	return
+}
+
+func (q *queueObserved) DequeueMultipleCtx(ctx context.Context, n int) (items []interface{}, err error) {
+	dequeuer, ok := q.queue.(goqueue.DequeuerCtx)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	start := time.Now()
+	items, err = dequeuer.DequeueMultipleCtx(ctx, n)
+	q.observe(start, Observation{
+		Operation: OperationDequeueMultipleCtx,
+		Count:     len(items),
+		Err:       err,
+	})
+	return
+}
+
+func (q *queueObserved) Enqueue(item interface{}) (overflow bool) {
+	enqueuer, ok := q.queue.(goqueue.Enqueuer)
+	if !ok {
+		return true
+	}
+	start := time.Now()
+	overflow = enqueuer.Enqueue(item)
+	observation := Observation{
+		Operation: OperationEnqueue,
+		Overflow:  overflow,
+	}
+	if !overflow {
+		observation.Count = 1
+	}
+	q.observe(start, observation)
+	return
+}
+
+func (q *queueObserved) EnqueueMultiple(items []interface{}) (itemsRemaining []interface{}, overflow bool) {
+	enqueuer, ok := q.queue.(goqueue.Enqueuer)
+	if !ok {
+		return items, true
+	}
+	start := time.Now()
+	itemsRemaining, overflow = enqueuer.EnqueueMultiple(items)
+	q.observe(start, Observation{
+		Operation: OperationEnqueueMultiple,
+		Count:     len(items) - len(itemsRemaining),
+		Overflow:  overflow,
+	})
+	return
+}
+
+func (q *queueObserved) EnqueueCtx(ctx context.Context, item interface{}) (err error) {
+	enqueuer, ok := q.queue.(goqueue.EnqueuerCtx)
+	if !ok {
+		return ErrNotSupported
+	}
+	start := time.Now()
+	err = enqueuer.EnqueueCtx(ctx, item)
+	observation := Observation{
+		Operation: OperationEnqueueCtx,
+		Err:       err,
+	}
+	if err == nil {
+		observation.Count = 1
+	}
+	q.observe(start, observation)
+	return
+}
+
+func (q *queueObserved) EnqueueMultipleCtx(ctx context.Context, items []interface{}) (itemsRemaining []interface{}, err error) {
+	enqueuer, ok := q.queue.(goqueue.EnqueuerCtx)
+	if !ok {
+		return items, ErrNotSupported
+	}
+	start := time.Now()
+	itemsRemaining, err = enqueuer.EnqueueMultipleCtx(ctx, items)
+	q.observe(start, Observation{
+		Operation: OperationEnqueueMultipleCtx,
+		Count:     len(items) - len(itemsRemaining),
+		Err:       err,
+	})
+	return
+}
+
+func (q *queueObserved) EnqueueInFront(item interface{}) (overflow bool) {
+	enqueuer, ok := q.queue.(goqueue.EnqueueInFronter)
+	if !ok {
+		return true
+	}
+	start := time.Now()
+	overflow = enqueuer.EnqueueInFront(item)
+	observation := Observation{
+		Operation: OperationEnqueueInFront,
+		Overflow:  overflow,
+	}
+	if !overflow {
+		observation.Count = 1
+	}
+	q.observe(start, observation)
+	return
+}
+
+func (q *queueObserved) EnqueueLossy(item interface{}) (discardedElement interface{}, discard bool) {
+	enqueuer, ok := q.queue.(finite.EnqueueLossy)
+	if !ok {
+		//KIM: the item is only discarded if the queue can't accept it
+		if q.Enqueue(item) {
+			return item, true
+		}
+		return nil, false
+	}
+	start := time.Now()
+	discardedElement, discard = enqueuer.EnqueueLossy(item)
+	q.observe(start, Observation{
+		Operation: OperationEnqueueLossy,
+		Count:     1,
+		Discard:   discard,
+	})
+	return
+}
+
+func (q *queueObserved) Resize(size int) (items []interface{}) {
+	resizer, ok := q.queue.(finite.Resizer)
+	if !ok {
+		return
+	}
+	start := time.Now()
+	items = resizer.Resize(size)
+	q.observe(start, Observation{
+		Operation: OperationResize,
+		Count:     len(items),
+	})
+	return
+}
+
+func (q *queueObserved) Peek() (items []interface{}) {
+	peeker, ok := q.queue.(goqueue.Peeker)
+	if !ok {
+		return
+	}
+	start := time.Now()
+	items = peeker.Peek()
+	q.observe(start, Observation{
+		Operation: OperationPeek,
+		Count:     len(items),
+		Underflow: len(items) <= 0,
+	})
+	return
+}
+
+func (q *queueObserved) PeekHead() (item interface{}, underflow bool) {
+	peeker, ok := q.queue.(goqueue.Peeker)
+	if !ok {
+		return nil, true
+	}
+	start := time.Now()
+	item, underflow = peeker.PeekHead()
+	observation := Observation{
+		Operation: OperationPeekHead,
+		Underflow: underflow,
+	}
+	if !underflow {
+		observation.Count = 1
+	}
+	q.observe(start, observation)
+	return
+}
+
+func (q *queueObserved) PeekFromHead(n int) (items []interface{}) {
+	peeker, ok := q.queue.(goqueue.Peeker)
+	if !ok {
+		return
+	}
+	start := time.Now()
+	items = peeker.PeekFromHead(n)
+	q.observe(start, Observation{
+		Operation: OperationPeekFromHead,
+		Count:     len(items),
+		Underflow: len(items) <= 0,
+	})
+	return
+}
+
+func (q *queueObserved) PeekHeadCtx(ctx context.Context) (item interface{}, err error) {
+	peeker, ok := q.queue.(goqueue.PeekerCtx)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	start := time.Now()
+	item, err = peeker.PeekHeadCtx(ctx)
+	observation := Observation{
+		Operation: OperationPeekHeadCtx,
+		Err:       err,
+	}
+	if err == nil {
+		observation.Count = 1
+	}
+	q.observe(start, observation)
+	return
+}
+
+func (q *queueObserved) PeekFromHeadCtx(ctx context.Context, n int) (items []interface{}, err error) {
+	peeker, ok := q.queue.(goqueue.PeekerCtx)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	start := time.Now()
+	items, err = peeker.PeekFromHeadCtx(ctx, n)
+	q.observe(start, Observation{
+		Operation: OperationPeekFromHeadCtx,
+		Count:     len(items),
+		Err:       err,
+	})
+	return
+}
+
+func (q *queueObserved) DequeueBack() (item interface{}, underflow bool) {
+	dequeuer, ok := q.queue.(goqueue.DequeueFromBacker)
+	if !ok {
+		return nil, true
+	}
+	start := time.Now()
+	item, underflow = dequeuer.DequeueBack()
+	observation := Observation{
+		Operation: OperationDequeueBack,
+		Underflow: underflow,
+	}
+	if !underflow {
+		observation.Count = 1
+	}
+	q.observe(start, observation)
+	return
+}
+
+func (q *queueObserved) DequeueMultipleBack(n int) (items []interface{}) {
+	dequeuer, ok := q.queue.(goqueue.DequeueFromBacker)
+	if !ok {
+		return
+	}
+	start := time.Now()
+	items = dequeuer.DequeueMultipleBack(n)
+	q.observe(start, Observation{
+		Operation: OperationDequeueMultipleBack,
+		Count:     len(items),
+		Underflow: len(items) <= 0,
+	})
+	return
+}
+
+func (q *queueObserved) DequeueIf(pred func(item interface{}) bool) (item interface{}, underflow bool) {
+	dequeuer, ok := q.queue.(goqueue.ConditionalDequeuer)
+	if !ok {
+		return nil, true
+	}
+	start := time.Now()
+	item, underflow = dequeuer.DequeueIf(pred)
+	observation := Observation{
+		Operation: OperationDequeueIf,
+		Underflow: underflow,
+	}
+	if !underflow {
+		observation.Count = 1
+	}
+	q.observe(start, observation)
+	return
+}
+
+func (q *queueObserved) DequeueWhile(pred func(item interface{}) bool, max int) (items []interface{}) {
+	dequeuer, ok := q.queue.(goqueue.ConditionalDequeuer)
+	if !ok {
+		return
+	}
+	start := time.Now()
+	items = dequeuer.DequeueWhile(pred, max)
+	q.observe(start, Observation{
+		Operation: OperationDequeueWhile,
+		Count:     len(items),
+		Underflow: len(items) <= 0,
+	})
+	return
+}
+
+func (q *queueObserved) PeekTail() (item interface{}, underflow bool) {
+	peeker, ok := q.queue.(goqueue.PeekFromTailer)
+	if !ok {
+		return nil, true
+	}
+	start := time.Now()
+	item, underflow = peeker.PeekTail()
+	observation := Observation{
+		Operation: OperationPeekTail,
+		Underflow: underflow,
+	}
+	if !underflow {
+		observation.Count = 1
+	}
+	q.observe(start, observation)
+	return
+}
+
+func (q *queueObserved) PeekFromTail(n int) (items []interface{}) {
+	peeker, ok := q.queue.(goqueue.PeekFromTailer)
+	if !ok {
+		return
+	}
+	start := time.Now()
+	items = peeker.PeekFromTail(n)
+	q.observe(start, Observation{
+		Operation: OperationPeekFromTail,
+		Count:     len(items),
+		Underflow: len(items) <= 0,
+	})
+	return
+}
+
+func (q *queueObserved) RemoveIf(pred func(item interface{}) bool) (items []interface{}) {
+	remover, ok := q.queue.(goqueue.Remover)
+	if !ok {
+		return
+	}
+	start := time.Now()
+	items = remover.RemoveIf(pred)
+	q.observe(start, Observation{
+		Operation: OperationRemoveIf,
+		Count:     len(items),
+	})
+	return
+}
+
+func (q *queueObserved) Find(pred func(item interface{}) bool) (index int, item interface{}, ok bool) {
+	remover, supported := q.queue.(goqueue.Remover)
+	if !supported {
+		return -1, nil, false
+	}
+	start := time.Now()
+	index, item, ok = remover.Find(pred)
+	observation := Observation{
+		Operation: OperationFind,
+	}
+	if ok {
+		observation.Count = 1
+	}
+	q.observe(start, observation)
+	return
+}
+
+func (q *queueObserved) Contains(pred func(item interface{}) bool) (ok bool) {
+	remover, supported := q.queue.(goqueue.Remover)
+	if !supported {
+		return false
+	}
+	start := time.Now()
+	ok = remover.Contains(pred)
+	observation := Observation{
+		Operation: OperationContains,
+	}
+	if ok {
+		observation.Count = 1
+	}
+	q.observe(start, observation)
+	return
+}
+
+func (q *queueObserved) Length() (size int) {
+	if length, ok := q.queue.(goqueue.Length); ok {
+		return length.Length()
+	}
+	return
+}
+
+func (q *queueObserved) Capacity() (capacity int) {
+	if c, ok := q.queue.(finite.Capacity); ok {
+		return c.Capacity()
+	}
+	return
+}
+
+func (q *queueObserved) Stats() (stats goqueue.Stats) {
+	if statser, ok := q.queue.(goqueue.Statser); ok {
+		return statser.Stats()
+	}
+	return
+}
+
+//GetSignalIn will return the signal of the queue, if the queue doesn't
+// implement Event, the signal will be nil (it'll never fire)
+func (q *queueObserved) GetSignalIn() (signal <-chan struct{}) {
+	if event, ok := q.queue.(goqueue.Event); ok {
+		return event.GetSignalIn()
+	}
+	return
+}
+
+//GetSignalOut will return the signal of the queue, if the queue doesn't
+// implement Event, the signal will be nil (it'll never fire)
+func (q *queueObserved) GetSignalOut() (signal <-chan struct{}) {
+	if event, ok := q.queue.(goqueue.Event); ok {
+		return event.GetSignalOut()
+	}
+	return
+}
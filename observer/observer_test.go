@@ -0,0 +1,423 @@
+package observer_test
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+	finite "github.com/antonio-alexander/go-queue/finite"
+	finite_tests "github.com/antonio-alexander/go-queue/finite/tests"
+	infinite "github.com/antonio-alexander/go-queue/infinite"
+	mpmc "github.com/antonio-alexander/go-queue/mpmc"
+	observer "github.com/antonio-alexander/go-queue/observer"
+	priority "github.com/antonio-alexander/go-queue/priority"
+	spsc "github.com/antonio-alexander/go-queue/spsc"
+	goqueue_tests "github.com/antonio-alexander/go-queue/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	mustTimeout = time.Second
+	mustRate    = time.Millisecond
+	casef       = "case: %s"
+)
+
+func init() {
+	rand.Seed(int64(time.Now().Nanosecond()))
+}
+
+type queueFinite interface {
+	goqueue.Owner
+	goqueue.GarbageCollecter
+	goqueue.Dequeuer
+	goqueue.DequeuerCtx
+	goqueue.Enqueuer
+	goqueue.EnqueuerCtx
+	goqueue.EnqueueInFronter
+	goqueue.Event
+	goqueue.Length
+	goqueue.Peeker
+	goqueue.PeekerCtx
+	goqueue.Statser
+	finite.EnqueueLossy
+	finite.Resizer
+	finite.Capacity
+}
+
+type recorder struct {
+	sync.Mutex
+	observations []observer.Observation
+}
+
+func (r *recorder) Observe(observation observer.Observation) {
+	r.Lock()
+	defer r.Unlock()
+	r.observations = append(r.observations, observation)
+}
+
+func (r *recorder) flush() (observations []observer.Observation) {
+	r.Lock()
+	defer r.Unlock()
+	observations, r.observations = r.observations, nil
+	return
+}
+
+//KIM: the observer shouldn't change the semantics of the wrapped queue, so
+// the wrapped queue should pass the same tests as the queue
+func TestQueue(t *testing.T) {
+	newQueue := func(size int) queueFinite {
+		return observer.New(finite.New(size), observer.ObserverFunc(func(observer.Observation) {})).(queueFinite)
+	}
+	t.Run("Test Dequeue", goqueue_tests.TestDequeue(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return newQueue(size)
+	}))
+	t.Run("Test Dequeue Event", goqueue_tests.TestDequeueEvent(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Event
+	} {
+		return newQueue(size)
+	}))
+	t.Run("Test Flush", goqueue_tests.TestFlush(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return newQueue(size)
+	}))
+	t.Run("Test Peek", goqueue_tests.TestPeek(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Peeker
+	} {
+		return newQueue(size)
+	}))
+	t.Run("Test Peek From Head", goqueue_tests.TestPeekFromHead(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Peeker
+	} {
+		return newQueue(size)
+	}))
+	t.Run("Test Dequeue Ctx", goqueue_tests.TestDequeueCtx(t, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.DequeuerCtx
+	} {
+		return newQueue(size)
+	}))
+	t.Run("Test Peek Ctx", goqueue_tests.TestPeekCtx(t, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Length
+		goqueue.PeekerCtx
+	} {
+		return newQueue(size)
+	}))
+	t.Run("Test Length", goqueue_tests.TestLength(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Length
+	} {
+		return newQueue(size)
+	}))
+	t.Run("Test Queue", goqueue_tests.TestQueue(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return newQueue(size)
+	}))
+	t.Run("Test Enqueue In Front", finite_tests.TestEnqueueInFront(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.EnqueueInFronter
+		goqueue.Peeker
+	} {
+		return newQueue(size)
+	}))
+	t.Run("Test Enqueue Lossy", finite_tests.TestEnqueueLossy(t, func(size int) interface {
+		goqueue.Owner
+		finite.EnqueueLossy
+	} {
+		return newQueue(size)
+	}))
+	t.Run("Test Resize", finite_tests.TestResize(t, func(size int) interface {
+		finite.Capacity
+		goqueue.Enqueuer
+		goqueue.Owner
+		finite.Resizer
+	} {
+		return newQueue(size)
+	}))
+}
+
+func TestObserver(t *testing.T) {
+	t.Run("Operations", func(t *testing.T) {
+		r := &recorder{}
+		q, ok := observer.New(finite.New(2), r).(queueFinite)
+		if !assert.True(t, ok) {
+			return
+		}
+		q.Enqueue(1)
+		q.EnqueueMultiple([]interface{}{2, 3})
+		q.Enqueue(4)
+		q.EnqueueLossy(5)
+		q.EnqueueInFront(6)
+		q.Peek()
+		q.PeekHead()
+		q.PeekFromHead(1)
+		q.Dequeue()
+		q.DequeueMultiple(1)
+		q.Dequeue()
+		q.Flush()
+		q.Enqueue(7)
+		q.Resize(1)
+		q.GarbageCollect()
+		q.DequeueCtx(context.Background())
+		q.EnqueueCtx(context.Background(), 8)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		q.DequeueMultipleCtx(ctx, 2)
+		q.Close()
+		expected := []observer.Observation{
+			{Operation: observer.OperationEnqueue, Count: 1},
+			{Operation: observer.OperationEnqueueMultiple, Count: 1, Overflow: true},
+			{Operation: observer.OperationEnqueue, Overflow: true},
+			{Operation: observer.OperationEnqueueLossy, Count: 1, Discard: true},
+			{Operation: observer.OperationEnqueueInFront, Overflow: true},
+			{Operation: observer.OperationPeek, Count: 2},
+			{Operation: observer.OperationPeekHead, Count: 1},
+			{Operation: observer.OperationPeekFromHead, Count: 1},
+			{Operation: observer.OperationDequeue, Count: 1},
+			{Operation: observer.OperationDequeueMultiple, Count: 1},
+			{Operation: observer.OperationDequeue, Underflow: true},
+			{Operation: observer.OperationFlush, Underflow: true},
+			{Operation: observer.OperationEnqueue, Count: 1},
+			{Operation: observer.OperationResize},
+			{Operation: observer.OperationGarbageCollect},
+			{Operation: observer.OperationDequeueCtx, Count: 1},
+			{Operation: observer.OperationEnqueueCtx, Count: 1},
+			{Operation: observer.OperationDequeueMultipleCtx, Count: 1, Err: context.Canceled},
+			{Operation: observer.OperationClose},
+		}
+		observations := r.flush()
+		if !assert.Len(t, observations, len(expected)) {
+			return
+		}
+		for i, observation := range observations {
+			assert.GreaterOrEqual(t, observation.Latency, time.Duration(0))
+			observation.Latency = 0
+			assert.Equal(t, expected[i], observation, "observation: %d", i)
+		}
+	})
+	t.Run("Not Supported", func(t *testing.T) {
+		//confirm that the wrapper only implements the interfaces the
+		// wrapped queue implements
+		r := &recorder{}
+		q := observer.New(priority.New(), r)
+		defer q.Close()
+		_, ok := q.(goqueue.GarbageCollecter)
+		assert.True(t, ok)
+		_, ok = q.(goqueue.EnqueueInFronter)
+		assert.False(t, ok)
+		_, ok = q.(goqueue.DequeuerCtx)
+		assert.False(t, ok)
+		_, ok = q.(goqueue.EnqueuerCtx)
+		assert.False(t, ok)
+		_, ok = q.(goqueue.PeekerCtx)
+		assert.False(t, ok)
+		_, ok = q.(goqueue.Statser)
+		assert.False(t, ok)
+		_, ok = q.(finite.EnqueueLossy)
+		assert.False(t, ok)
+		_, ok = q.(finite.Resizer)
+		assert.False(t, ok)
+		_, ok = q.(finite.Capacity)
+		assert.False(t, ok)
+		assert.False(t, q.Enqueue(1))
+		assert.Equal(t, 1, q.Length())
+		assert.Len(t, r.flush(), 1)
+
+		//confirm that the infinite queue isn't treated as a finite queue
+		q = observer.New(infinite.New(1), r)
+		defer q.Close()
+		_, ok = q.(goqueue.DequeuerCtx)
+		assert.True(t, ok)
+		_, ok = q.(goqueue.Statser)
+		assert.True(t, ok)
+		_, ok = q.(finite.EnqueueLossy)
+		assert.False(t, ok)
+		_, ok = q.(finite.Capacity)
+		assert.False(t, ok)
+	})
+	t.Run("Interfaces", func(t *testing.T) {
+		//confirm that the wrapper implements every interface the wrapped
+		// queue implements (and none that it doesn't)
+		type implements struct {
+			gc, inFront, dequeueBack, peekTail, conditional, remover bool
+			dequeueCtx, enqueueCtx, peekCtx, stats                   bool
+			lossy, resizer, capacity                                 bool
+		}
+		cases := map[string]struct {
+			iQueue      func() goqueue.Owner
+			oImplements implements
+		}{
+			"finite": {
+				iQueue: func() goqueue.Owner { return finite.New(1) },
+				oImplements: implements{
+					gc: true, inFront: true, dequeueBack: true, peekTail: true,
+					conditional: true, remover: true, dequeueCtx: true,
+					enqueueCtx: true, peekCtx: true, stats: true, lossy: true,
+					resizer: true, capacity: true,
+				},
+			},
+			"infinite": {
+				iQueue: func() goqueue.Owner { return infinite.New(1) },
+				oImplements: implements{
+					gc: true, inFront: true, dequeueBack: true, peekTail: true,
+					conditional: true, remover: true, dequeueCtx: true,
+					enqueueCtx: true, peekCtx: true, stats: true,
+				},
+			},
+			"mpmc": {
+				iQueue: func() goqueue.Owner { return mpmc.New(1) },
+				oImplements: implements{
+					gc: true, inFront: true, dequeueCtx: true, enqueueCtx: true,
+					peekCtx: true, stats: true, lossy: true, resizer: true,
+					capacity: true,
+				},
+			},
+			"priority": {
+				iQueue:      func() goqueue.Owner { return priority.New() },
+				oImplements: implements{gc: true},
+			},
+			"spsc": {
+				iQueue:      func() goqueue.Owner { return spsc.New(1) },
+				oImplements: implements{},
+			},
+		}
+		for cDesc, c := range cases {
+			q := observer.New(c.iQueue(), &recorder{})
+			var i implements
+			_, i.gc = q.(goqueue.GarbageCollecter)
+			_, i.inFront = q.(goqueue.EnqueueInFronter)
+			_, i.dequeueBack = q.(goqueue.DequeueFromBacker)
+			_, i.peekTail = q.(goqueue.PeekFromTailer)
+			_, i.conditional = q.(goqueue.ConditionalDequeuer)
+			_, i.remover = q.(goqueue.Remover)
+			_, i.dequeueCtx = q.(goqueue.DequeuerCtx)
+			_, i.enqueueCtx = q.(goqueue.EnqueuerCtx)
+			_, i.peekCtx = q.(goqueue.PeekerCtx)
+			_, i.stats = q.(goqueue.Statser)
+			_, i.lossy = q.(finite.EnqueueLossy)
+			_, i.resizer = q.(finite.Resizer)
+			_, i.capacity = q.(finite.Capacity)
+			assert.Equal(t, c.oImplements, i, casef, cDesc)
+			q.Close()
+		}
+	})
+	t.Run("Extended Operations", func(t *testing.T) {
+		r := &recorder{}
+		q := observer.New(infinite.New(4), r)
+		defer q.Close()
+		type queueExtended interface {
+			goqueue.DequeueFromBacker
+			goqueue.PeekFromTailer
+			goqueue.ConditionalDequeuer
+			goqueue.Remover
+		}
+		extended, ok := q.(queueExtended)
+		if !assert.True(t, ok) {
+			return
+		}
+		isEven := func(item interface{}) bool { return item.(int)%2 == 0 }
+		q.EnqueueMultiple([]interface{}{1, 2, 3, 4, 5, 6})
+		r.flush()
+		extended.PeekTail()
+		extended.PeekFromTail(2)
+		extended.DequeueBack()
+		extended.DequeueMultipleBack(1)
+		extended.DequeueIf(isEven)
+		extended.DequeueWhile(isEven, 2)
+		extended.Find(isEven)
+		extended.Contains(isEven)
+		extended.RemoveIf(isEven)
+		extended.Contains(isEven)
+		expected := []observer.Observation{
+			{Operation: observer.OperationPeekTail, Count: 1},
+			{Operation: observer.OperationPeekFromTail, Count: 2},
+			{Operation: observer.OperationDequeueBack, Count: 1},
+			{Operation: observer.OperationDequeueMultipleBack, Count: 1},
+			{Operation: observer.OperationDequeueIf, Underflow: true},
+			{Operation: observer.OperationDequeueWhile, Underflow: true},
+			{Operation: observer.OperationFind, Count: 1},
+			{Operation: observer.OperationContains, Count: 1},
+			{Operation: observer.OperationRemoveIf, Count: 2},
+			{Operation: observer.OperationContains},
+		}
+		observations := r.flush()
+		if !assert.Len(t, observations, len(expected)) {
+			return
+		}
+		for i, observation := range observations {
+			observation.Latency = 0
+			assert.Equal(t, expected[i], observation, "observation: %d", i)
+		}
+		assert.Equal(t, []interface{}{1, 3}, q.Flush())
+	})
+	t.Run("Channel Lossy", func(t *testing.T) {
+		//KIM: FromChannel will fall back to Enqueue() if the queue doesn't
+		// implement EnqueueLossy(), so none of the items should be dropped
+		q := observer.New(infinite.New(4), &recorder{})
+		defer q.Close()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		items := make(chan interface{})
+		undelivered := goqueue.FromChannel(ctx, items, q,
+			goqueue.WithChannelOverflow(goqueue.ChannelLossy))
+		for i := 0; i < 8; i++ {
+			items <- i
+		}
+		close(items)
+		select {
+		case <-time.After(mustTimeout):
+			assert.Fail(t, "unable to confirm undelivered")
+		case remaining := <-undelivered:
+			assert.Empty(t, remaining)
+		}
+		assert.Equal(t, []interface{}{0, 1, 2, 3, 4, 5, 6, 7}, q.Flush())
+	})
+	t.Run("Latency", func(t *testing.T) {
+		const wait = 10 * time.Millisecond
+
+		r := &recorder{}
+		q := observer.New(infinite.New(1), r)
+		defer q.Close()
+		dequeuer, ok := q.(goqueue.DequeuerCtx)
+		if !assert.True(t, ok) {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), wait)
+		defer cancel()
+		_, err := dequeuer.DequeueCtx(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		observations := r.flush()
+		if assert.Len(t, observations, 1) {
+			assert.GreaterOrEqual(t, observations[0].Latency, wait)
+			assert.ErrorIs(t, observations[0].Err, context.DeadlineExceeded)
+		}
+	})
+}
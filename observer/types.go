@@ -0,0 +1,74 @@
+package observer
+
+import (
+	"errors"
+	"time"
+)
+
+//ErrNotSupported will be returned by the context-aware operations if the
+// wrapped queue doesn't implement them
+var ErrNotSupported = errors.New("operation not supported")
+
+//Operation describes the queue operation that was observed
+type Operation string
+
+//These are the operations that can be observed
+const (
+	OperationEnqueue             Operation = "enqueue"
+	OperationEnqueueMultiple     Operation = "enqueue_multiple"
+	OperationEnqueueInFront      Operation = "enqueue_in_front"
+	OperationEnqueueLossy        Operation = "enqueue_lossy"
+	OperationEnqueueCtx          Operation = "enqueue_ctx"
+	OperationEnqueueMultipleCtx  Operation = "enqueue_multiple_ctx"
+	OperationDequeue             Operation = "dequeue"
+	OperationDequeueMultiple     Operation = "dequeue_multiple"
+	OperationDequeueCtx          Operation = "dequeue_ctx"
+	OperationDequeueMultipleCtx  Operation = "dequeue_multiple_ctx"
+	OperationDequeueBack         Operation = "dequeue_back"
+	OperationDequeueMultipleBack Operation = "dequeue_multiple_back"
+	OperationDequeueIf           Operation = "dequeue_if"
+	OperationDequeueWhile        Operation = "dequeue_while"
+	OperationFlush               Operation = "flush"
+	OperationPeek                Operation = "peek"
+	OperationPeekHead            Operation = "peek_head"
+	OperationPeekFromHead        Operation = "peek_from_head"
+	OperationPeekHeadCtx         Operation = "peek_head_ctx"
+	OperationPeekFromHeadCtx     Operation = "peek_from_head_ctx"
+	OperationPeekTail            Operation = "peek_tail"
+	OperationPeekFromTail        Operation = "peek_from_tail"
+	OperationRemoveIf            Operation = "remove_if"
+	OperationFind                Operation = "find"
+	OperationContains            Operation = "contains"
+	OperationResize              Operation = "resize"
+	OperationGarbageCollect      Operation = "garbage_collect"
+	OperationClose               Operation = "close"
+)
+
+//Observation describes the result of a single queue operation. Count is the
+// number of items that went in or came out of the queue (or were peeked);
+// for Close() and Resize() it's the number of items returned. Discard will
+// be true if EnqueueLossy() discarded an item and Err is the error returned
+// by a context-aware operation
+type Observation struct {
+	Operation Operation
+	Count     int
+	Overflow  bool
+	Underflow bool
+	Discard   bool
+	Err       error
+	Latency   time.Duration
+}
+
+//Observer will be called after each operation, it's called synchronously
+// (so it should be quick) and should be safe for concurrent use
+type Observer interface {
+	Observe(observation Observation)
+}
+
+//ObserverFunc can be used to use a function as an Observer
+type ObserverFunc func(observation Observation)
+
+//Observe will call the function with the observation
+func (f ObserverFunc) Observe(observation Observation) {
+	f(observation)
+}
@@ -0,0 +1,10 @@
+// Copyright 2022 antonio-alexander. All rights reserved.
+// Use of this source code is governed by an MIT
+// license that can be found in the LICENSE file.
+
+/*
+	Package persistent provides a durable queue implementation that writes
+	every operation to an append-only log on disk such that the contents of
+	the queue can be recovered on restart
+*/
+package persistent
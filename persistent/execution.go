@@ -0,0 +1,150 @@
+package persistent
+
+import (
+	"bufio"
+	"container/list"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+)
+
+//these are the operations that can be written to the log, every record
+// in the log is made up of a header (operation, payload length and a
+// checksum of the operation and payload) followed by the payload
+const (
+	opEnqueue byte = iota + 1
+	opEnqueueInFront
+	opDequeue
+)
+
+const headerSize = 1 + 4 + 4
+
+var errUnsupportedItem = errors.New("item doesn't support binary marshalling")
+
+//marshal will convert an item into bytes, the item must be a slice of
+// bytes or implement BinaryMarshaler
+func marshal(item interface{}) ([]byte, error) {
+	switch v := item.(type) {
+	default:
+		return nil, errUnsupportedItem
+	case goqueue.Bytes:
+		return append([]byte(nil), v...), nil
+	case []byte:
+		return append([]byte(nil), v...), nil
+	case goqueue.BinaryMarshaler:
+		return v.MarshalBinary()
+	}
+}
+
+//encodeRecord will create a record for the given operation and payload
+// that can be appended to the log
+func encodeRecord(op byte, payload []byte) []byte {
+	record := make([]byte, headerSize+len(payload))
+	record[0] = op
+	binary.BigEndian.PutUint32(record[1:5], uint32(len(payload)))
+	copy(record[headerSize:], payload)
+	checksum := crc32.NewIEEE()
+	checksum.Write(record[:5])
+	checksum.Write(payload)
+	binary.BigEndian.PutUint32(record[5:9], checksum.Sum32())
+	return record
+}
+
+//encodeDequeue will create a record that removes n items from the
+// front of the queue
+func encodeDequeue(n int) []byte {
+	payload := make([]byte, 4)
+	binary.BigEndian.PutUint32(payload, uint32(n))
+	return encodeRecord(opDequeue, payload)
+}
+
+//readRecord will read a single record from the reader, it will return
+// io.EOF if there are no more records and io.ErrUnexpectedEOF if the
+// record is incomplete or corrupt (e.g. a torn write). The remaining size
+// is the number of bytes left to read, the payload length is checked
+// against it before the payload is allocated since it can't be trusted
+// until the checksum is verified
+func readRecord(reader io.Reader, remaining int64) (op byte, payload []byte, err error) {
+	header := make([]byte, headerSize)
+	if _, err = io.ReadFull(reader, header); err != nil {
+		return
+	}
+	size := int64(binary.BigEndian.Uint32(header[1:5]))
+	if size > remaining-headerSize {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	payload = make([]byte, size)
+	if _, err = io.ReadFull(reader, payload); err != nil {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	checksum := crc32.NewIEEE()
+	checksum.Write(header[:5])
+	checksum.Write(payload)
+	if checksum.Sum32() != binary.BigEndian.Uint32(header[5:9]) {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	if header[0] == opDequeue && len(payload) != 4 {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	return header[0], payload, nil
+}
+
+//replay will read all of the records from the file and apply them to
+// a list to recover the contents of the queue; if the end of the file
+// contains a partial or corrupt record, the file will be truncated to
+// the last good record. The number of records read will be returned
+func replay(file *os.File) (data *list.List, records int, err error) {
+	var offset int64
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+	data = list.New()
+	reader := bufio.NewReader(file)
+	for {
+		op, payload, err := readRecord(reader, info.Size()-offset)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return data, records, nil
+			}
+			if !errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil, 0, err
+			}
+			//KIM: this is likely a torn write from a crash, everything
+			// after the last good record is discarded
+			if err := file.Truncate(offset); err != nil {
+				return nil, 0, err
+			}
+			return data, records, nil
+		}
+		switch op {
+		case opEnqueue:
+			data.PushBack(goqueue.Bytes(payload))
+		case opEnqueueInFront:
+			data.PushFront(goqueue.Bytes(payload))
+		case opDequeue:
+			for n := binary.BigEndian.Uint32(payload); n > 0 && data.Len() > 0; n-- {
+				data.Remove(data.Front())
+			}
+		}
+		offset += int64(headerSize + len(payload))
+		records++
+	}
+}
+
+//syncDir will sync the directory that contains the file at the given path
+// so that a rename (or creation) of the file is durable
+func syncDir(path string) error {
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
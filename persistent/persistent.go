@@ -0,0 +1,382 @@
+package persistent
+
+import (
+	"container/list"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+	internal "github.com/antonio-alexander/go-queue/internal"
+)
+
+type queuePersistent struct {
+	sync.RWMutex
+	sync.WaitGroup
+	configuration
+	path      string
+	file      *os.File
+	offset    int64
+	records   int
+	unsynced  int
+	data      *list.List
+	signalIn  chan struct{}
+	signalOut chan struct{}
+	stopper   chan struct{}
+}
+
+//New can be used to open (or create) a persistent queue backed by the log
+// at the given path, any items in the log will be recovered in the order
+// they were enqueued. Items must be a slice of bytes or implement
+// BinaryMarshaler, they will always be returned as goqueue.Bytes
+func New(path string, options ...Option) (interface {
+	goqueue.Owner
+	goqueue.Dequeuer
+	goqueue.Enqueuer
+	goqueue.EnqueueInFronter
+	goqueue.Length
+	goqueue.Event
+	goqueue.Peeker
+	Syncer
+}, error) {
+	q := &queuePersistent{
+		configuration: configuration{
+			syncPolicy:       SyncEveryOperation,
+			compactThreshold: DefaultCompactThreshold,
+		},
+		path:      path,
+		signalIn:  make(chan struct{}, 1),
+		signalOut: make(chan struct{}, 1),
+		stopper:   make(chan struct{}),
+	}
+	for _, option := range options {
+		option(&q.configuration)
+	}
+	if err := q.open(); err != nil {
+		return nil, err
+	}
+	if q.syncPolicy == SyncInterval {
+		q.launchSync()
+	}
+	return q, nil
+}
+
+//open will open the log, replay its records and position the file such
+// that new records are appended
+func (q *queuePersistent) open() error {
+	file, err := os.OpenFile(q.path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	data, records, err := replay(file)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	offset, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	q.file, q.data, q.records, q.offset = file, data, records, offset
+	return nil
+}
+
+//launchSync will start a go routine that periodically syncs the log
+// if any operations have been written since the last sync
+func (q *queuePersistent) launchSync() {
+	q.Add(1)
+	go func() {
+		defer q.Done()
+
+		tSync := time.NewTicker(q.syncInterval)
+		defer tSync.Stop()
+		for {
+			select {
+			case <-q.stopper:
+				return
+			case <-tSync.C:
+				q.Lock()
+				if q.file != nil && q.unsynced > 0 {
+					if err := q.file.Sync(); err == nil {
+						q.unsynced = 0
+					}
+				}
+				q.Unlock()
+			}
+		}
+	}()
+}
+
+//truncate will remove everything in the log after the given offset and
+// position the file such that new records are appended at the offset
+func (q *queuePersistent) truncate(offset int64) {
+	_ = q.file.Truncate(offset)
+	_, _ = q.file.Seek(offset, io.SeekStart)
+}
+
+//write will append one or more records to the log and sync according
+// to the sync policy, if the write or sync fails, the log will be
+// truncated to remove the records such that the log matches the data
+// (the operation won't be applied)
+func (q *queuePersistent) write(records ...[]byte) error {
+	var buffer []byte
+
+	if q.file == nil {
+		return goqueue.ErrQueueClosed
+	}
+	for _, record := range records {
+		buffer = append(buffer, record...)
+	}
+	if _, err := q.file.Write(buffer); err != nil {
+		q.truncate(q.offset)
+		return err
+	}
+	sync := q.syncPolicy == SyncEveryOperation ||
+		(q.syncPolicy == SyncEveryN && q.unsynced+len(records) >= q.syncN)
+	if sync {
+		if err := q.file.Sync(); err != nil {
+			q.truncate(q.offset)
+			return err
+		}
+	}
+	q.offset += int64(len(buffer))
+	q.records += len(records)
+	q.unsynced += len(records)
+	if sync {
+		q.unsynced = 0
+	}
+	return nil
+}
+
+//compact will re-write the log such that it only contains the items in
+// the queue once the number of records grows past the threshold; the log
+// is written to a temporary file that replaces the log once it's synced
+// so a crash during compaction can't lose any data. If compaction fails
+// the existing log is kept as-is. It must be called after the operation
+// that was written to the log has been applied to the data, otherwise the
+// compacted log won't contain the operation
+func (q *queuePersistent) compact() {
+	var buffer []byte
+
+	if q.records < q.compactThreshold || q.records < 2*q.data.Len() {
+		return
+	}
+	path := q.path + ".compact"
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return
+	}
+	for e := q.data.Front(); e != nil; e = e.Next() {
+		buffer = append(buffer, encodeRecord(opEnqueue, e.Value.(goqueue.Bytes))...)
+	}
+	if _, err := file.Write(buffer); err != nil {
+		file.Close()
+		os.Remove(path)
+		return
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(path)
+		return
+	}
+	if err := os.Rename(path, q.path); err != nil {
+		file.Close()
+		os.Remove(path)
+		return
+	}
+	//KIM: the rename isn't durable until the directory is synced, if
+	// this fails the rename may be lost on a crash, but the old log
+	// still contains every operation
+	_ = syncDir(q.path)
+	q.file.Close()
+	q.file, q.offset = file, int64(len(buffer))
+	q.records, q.unsynced = q.data.Len(), 0
+}
+
+func (q *queuePersistent) enqueue(op byte, items []interface{}) (remainingElements []interface{}, overflow bool) {
+	records := make([][]byte, 0, len(items))
+	values := make([]goqueue.Bytes, 0, len(items))
+	for i, item := range items {
+		bytes, err := marshal(item)
+		if err != nil {
+			remainingElements, overflow = items[i:], true
+			break
+		}
+		records = append(records, encodeRecord(op, bytes))
+		values = append(values, bytes)
+	}
+	if len(records) == 0 {
+		return
+	}
+	if err := q.write(records...); err != nil {
+		return items, true
+	}
+	for _, value := range values {
+		switch op {
+		case opEnqueueInFront:
+			q.data.PushFront(value)
+		default:
+			q.data.PushBack(value)
+		}
+		internal.SendSignal(q.signalIn)
+	}
+	q.compact()
+	return
+}
+
+func (q *queuePersistent) dequeueMultiple(n int) (items []interface{}) {
+	if n > q.data.Len() {
+		n = q.data.Len()
+	}
+	if n <= 0 {
+		return
+	}
+	if err := q.write(encodeDequeue(n)); err != nil {
+		return
+	}
+	items = make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		items = append(items, q.data.Remove(q.data.Front()))
+	}
+	internal.SendSignal(q.signalOut)
+	q.compact()
+	return
+}
+
+func (q *queuePersistent) peekFromHead(n int) (items []interface{}) {
+	for e := q.data.Front(); e != nil && len(items) < n; e = e.Next() {
+		items = append(items, e.Value)
+	}
+	return
+}
+
+//Close will sync and close the log and return the items that remain in
+// the queue, keep in mind that the items remain in the log and will be
+// recovered the next time the queue is opened
+func (q *queuePersistent) Close() (remainingElements []interface{}) {
+	select {
+	default:
+		close(q.stopper)
+	case <-q.stopper:
+	}
+	q.Wait()
+
+	q.Lock()
+	defer q.Unlock()
+
+	if q.file == nil {
+		return
+	}
+	_ = q.file.Sync()
+	_ = q.file.Close()
+	remainingElements = q.peekFromHead(q.data.Len())
+	close(q.signalIn)
+	close(q.signalOut)
+	q.file, q.data = nil, list.New()
+
+	return
+}
+
+func (q *queuePersistent) Sync() (err error) {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.file == nil {
+		return goqueue.ErrQueueClosed
+	}
+	if err = q.file.Sync(); err != nil {
+		return
+	}
+	q.unsynced = 0
+
+	return
+}
+
+func (q *queuePersistent) GetSignalIn() (signal <-chan struct{}) {
+	q.RLock()
+	defer q.RUnlock()
+	return q.signalIn
+}
+
+func (q *queuePersistent) GetSignalOut() (signal <-chan struct{}) {
+	q.RLock()
+	defer q.RUnlock()
+	return q.signalOut
+}
+
+func (q *queuePersistent) Dequeue() (item interface{}, underflow bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	items := q.dequeueMultiple(1)
+	if len(items) <= 0 {
+		return nil, true
+	}
+	return items[0], false
+}
+
+func (q *queuePersistent) DequeueMultiple(n int) (items []interface{}) {
+	q.Lock()
+	defer q.Unlock()
+	return q.dequeueMultiple(n)
+}
+
+func (q *queuePersistent) Flush() (items []interface{}) {
+	q.Lock()
+	defer q.Unlock()
+	return q.dequeueMultiple(q.data.Len())
+}
+
+func (q *queuePersistent) Enqueue(item interface{}) (overflow bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	_, overflow = q.enqueue(opEnqueue, []interface{}{item})
+
+	return
+}
+
+func (q *queuePersistent) EnqueueMultiple(items []interface{}) (remainingElements []interface{}, overflow bool) {
+	q.Lock()
+	defer q.Unlock()
+	return q.enqueue(opEnqueue, items)
+}
+
+func (q *queuePersistent) EnqueueInFront(item interface{}) (overflow bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	_, overflow = q.enqueue(opEnqueueInFront, []interface{}{item})
+
+	return
+}
+
+func (q *queuePersistent) Length() (size int) {
+	q.RLock()
+	defer q.RUnlock()
+	return q.data.Len()
+}
+
+func (q *queuePersistent) Peek() (items []interface{}) {
+	q.RLock()
+	defer q.RUnlock()
+	return q.peekFromHead(q.data.Len())
+}
+
+func (q *queuePersistent) PeekHead() (item interface{}, underflow bool) {
+	q.RLock()
+	defer q.RUnlock()
+
+	if q.data.Len() <= 0 {
+		return nil, true
+	}
+	return q.data.Front().Value, false
+}
+
+func (q *queuePersistent) PeekFromHead(n int) (items []interface{}) {
+	q.RLock()
+	defer q.RUnlock()
+	return q.peekFromHead(n)
+}
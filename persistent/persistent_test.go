@@ -0,0 +1,377 @@
+package persistent_test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+	infinite_tests "github.com/antonio-alexander/go-queue/infinite/tests"
+	persistent "github.com/antonio-alexander/go-queue/persistent"
+	goqueue_tests "github.com/antonio-alexander/go-queue/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	mustTimeout = time.Second
+	mustRate    = time.Millisecond
+	casef       = "case: %s"
+)
+
+var nQueues int64
+
+func init() {
+	rand.Seed(int64(time.Now().Nanosecond()))
+}
+
+type queue interface {
+	goqueue.Owner
+	goqueue.Dequeuer
+	goqueue.Enqueuer
+	goqueue.EnqueueInFronter
+	goqueue.Length
+	goqueue.Event
+	goqueue.Peeker
+	persistent.Syncer
+}
+
+//newQueue will create a persistent queue with a unique log in a temporary
+// directory that's removed when the test completes
+func newQueue(t *testing.T, options ...persistent.Option) queue {
+	path := filepath.Join(t.TempDir(), fmt.Sprintf("queue_%d.log", atomic.AddInt64(&nQueues, 1)))
+	q, err := persistent.New(path, options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q
+}
+
+func TestQueue(t *testing.T) {
+	t.Run("Test Dequeue", goqueue_tests.TestDequeue(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return newQueue(t)
+	}))
+	t.Run("Test Dequeue Event", goqueue_tests.TestDequeueEvent(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Dequeuer
+		goqueue.Enqueuer
+		goqueue.Event
+		goqueue.Owner
+	} {
+		return newQueue(t)
+	}))
+	t.Run("Test Dequeue Multiple", goqueue_tests.TestDequeueMultiple(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return newQueue(t)
+	}))
+	t.Run("Test Flush", goqueue_tests.TestFlush(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return newQueue(t)
+	}))
+	t.Run("Test Peek", goqueue_tests.TestPeek(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Peeker
+	} {
+		return newQueue(t)
+	}))
+	t.Run("Test Peek From Head", goqueue_tests.TestPeekFromHead(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Peeker
+	} {
+		return newQueue(t)
+	}))
+	t.Run("Test Event", goqueue_tests.TestEvent(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Event
+	} {
+		return newQueue(t)
+	}))
+	t.Run("Test Length", goqueue_tests.TestLength(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Length
+	} {
+		return newQueue(t)
+	}))
+	t.Run("Test Queue", goqueue_tests.TestQueue(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return newQueue(t)
+	}))
+	t.Run("Test Asynchronous", goqueue_tests.TestAsync(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return newQueue(t, persistent.WithSyncEveryN(100))
+	}))
+	t.Run("Test Enqueue", infinite_tests.TestEnqueue(t, mustRate, mustTimeout, func() interface {
+		goqueue.Dequeuer
+		goqueue.Enqueuer
+		goqueue.Owner
+	} {
+		return newQueue(t)
+	}))
+	t.Run("Test Enqueue In Front", infinite_tests.TestEnqueueInFront(t, mustRate, mustTimeout, func() interface {
+		goqueue.Dequeuer
+		goqueue.EnqueueInFronter
+		goqueue.Enqueuer
+		goqueue.Owner
+	} {
+		return newQueue(t)
+	}))
+}
+
+func TestPersistentQueue(t *testing.T) {
+	t.Run("Recover", func(t *testing.T) {
+		options := map[string][]persistent.Option{
+			"every_operation": {persistent.WithSyncEveryOperation()},
+			"every_n":         {persistent.WithSyncEveryN(3)},
+			"interval":        {persistent.WithSyncInterval(time.Millisecond)},
+		}
+		for cDesc, c := range options {
+			path := filepath.Join(t.TempDir(), "queue.log")
+			examples := goqueue.ExampleGen(10)
+
+			//enqueue, dequeue and enqueue in front
+			q, err := persistent.New(path, c...)
+			if !assert.Nil(t, err, "case: %s", cDesc) {
+				continue
+			}
+			values, overflow := goqueue.ExampleEnqueueMultiple(q, examples[:8])
+			assert.False(t, overflow, "case: %s", cDesc)
+			assert.Empty(t, values, "case: %s", cDesc)
+			assert.Len(t, goqueue.ExampleDequeueMultiple(q, 3), 3, "case: %s", cDesc)
+			assert.False(t, q.EnqueueInFront(examples[8]), "case: %s", cDesc)
+			assert.False(t, q.Enqueue(examples[9]), "case: %s", cDesc)
+			expected := append(append([]*goqueue.Example{examples[8]}, examples[3:8]...), examples[9])
+			assert.Equal(t, expected, goqueue.ExamplePeek(q), "case: %s", cDesc)
+			assert.Equal(t, expected, goqueue.ExampleClose(q), "case: %s", cDesc)
+
+			//re-open the queue and confirm the contents were recovered
+			q, err = persistent.New(path, c...)
+			if !assert.Nil(t, err, "case: %s", cDesc) {
+				continue
+			}
+			assert.Equal(t, len(expected), q.Length(), "case: %s", cDesc)
+			assert.Equal(t, expected, goqueue.ExampleFlush(q), "case: %s", cDesc)
+			q.Close()
+
+			//re-open the queue and confirm the flush was recovered
+			q, err = persistent.New(path, c...)
+			if !assert.Nil(t, err, "case: %s", cDesc) {
+				continue
+			}
+			assert.Equal(t, 0, q.Length(), "case: %s", cDesc)
+			q.Close()
+		}
+	})
+	t.Run("Torn Write", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "queue.log")
+		examples := goqueue.ExampleGen(5)
+
+		//enqueue the examples and simulate a partially written record
+		q, err := persistent.New(path)
+		if !assert.Nil(t, err) {
+			return
+		}
+		_, overflow := goqueue.ExampleEnqueueMultiple(q, examples)
+		assert.False(t, overflow)
+		q.Close()
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+		if !assert.Nil(t, err) {
+			return
+		}
+		_, err = file.Write([]byte{1, 0, 0, 0, 100, 1, 2})
+		assert.Nil(t, err)
+		file.Close()
+
+		//re-open the queue, confirm that the partial record was discarded
+		// and that the log can still be appended to
+		q, err = persistent.New(path)
+		if !assert.Nil(t, err) {
+			return
+		}
+		assert.Equal(t, examples, goqueue.ExamplePeek(q))
+		example := &goqueue.Example{Int: rand.Int()}
+		assert.False(t, q.Enqueue(example))
+		q.Close()
+		q, err = persistent.New(path)
+		if !assert.Nil(t, err) {
+			return
+		}
+		assert.Equal(t, append(examples, example), goqueue.ExampleClose(q))
+	})
+	t.Run("Corrupt Record", func(t *testing.T) {
+		//record will encode a record with a valid checksum
+		record := func(op byte, payload []byte) []byte {
+			header := make([]byte, 9)
+			header[0] = op
+			binary.BigEndian.PutUint32(header[1:5], uint32(len(payload)))
+			checksum := crc32.NewIEEE()
+			checksum.Write(header[:5])
+			checksum.Write(payload)
+			binary.BigEndian.PutUint32(header[5:9], checksum.Sum32())
+			return append(header, payload...)
+		}
+		cases := map[string]struct {
+			iBytes []byte
+		}{
+			"huge length": {
+				iBytes: []byte{1, 0xFF, 0xFF, 0xFF, 0xF0, 0, 0, 0, 0},
+			},
+			"short dequeue": {
+				iBytes: record(3, []byte{0, 1}),
+			},
+		}
+		for cDesc, c := range cases {
+			path := filepath.Join(t.TempDir(), "queue.log")
+			examples := goqueue.ExampleGen(5)
+
+			//enqueue the examples and append the corrupt record
+			q, err := persistent.New(path)
+			if !assert.Nil(t, err, casef, cDesc) {
+				continue
+			}
+			_, overflow := goqueue.ExampleEnqueueMultiple(q, examples)
+			assert.False(t, overflow, casef, cDesc)
+			q.Close()
+			file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+			if !assert.Nil(t, err, casef, cDesc) {
+				continue
+			}
+			_, err = file.Write(c.iBytes)
+			assert.Nil(t, err, casef, cDesc)
+			file.Close()
+
+			//re-open the queue, confirm that the corrupt record was
+			// discarded without allocating its length
+			var before, after runtime.MemStats
+			runtime.ReadMemStats(&before)
+			q, err = persistent.New(path)
+			runtime.ReadMemStats(&after)
+			if !assert.Nil(t, err, casef, cDesc) {
+				continue
+			}
+			assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(1<<20), casef, cDesc)
+			assert.Equal(t, examples, goqueue.ExampleClose(q), casef, cDesc)
+		}
+	})
+	t.Run("Compact", func(t *testing.T) {
+		const threshold = 16
+
+		path := filepath.Join(t.TempDir(), "queue.log")
+		q, err := persistent.New(path, persistent.WithCompactThreshold(threshold))
+		if !assert.Nil(t, err) {
+			return
+		}
+		examples := goqueue.ExampleGen(10 * threshold)
+		for _, example := range examples {
+			assert.False(t, q.Enqueue(example))
+			_, underflow := q.Dequeue()
+			assert.False(t, underflow)
+		}
+		example := &goqueue.Example{Int: rand.Int()}
+		assert.False(t, q.Enqueue(example))
+		q.Close()
+
+		//confirm that the log didn't grow with every operation
+		info, err := os.Stat(path)
+		if assert.Nil(t, err) {
+			bytes, _ := example.MarshalBinary()
+			assert.Less(t, info.Size(), int64(2*threshold*(len(bytes)+64)))
+		}
+		q, err = persistent.New(path)
+		if !assert.Nil(t, err) {
+			return
+		}
+		assert.Equal(t, []*goqueue.Example{example}, goqueue.ExampleClose(q))
+	})
+	t.Run("Compact Reopen", func(t *testing.T) {
+		cases := map[string]struct {
+			iThreshold int
+			iEnqueue   []interface{}
+			iDequeue   int
+			oItems     []interface{}
+		}{
+			"enqueue": {
+				iThreshold: 1,
+				iEnqueue:   []interface{}{goqueue.Bytes("a")},
+				oItems:     []interface{}{goqueue.Bytes("a")},
+			},
+			"enqueue multiple": {
+				iThreshold: 1,
+				iEnqueue:   []interface{}{goqueue.Bytes("a"), goqueue.Bytes("b"), goqueue.Bytes("c")},
+				oItems:     []interface{}{goqueue.Bytes("a"), goqueue.Bytes("b"), goqueue.Bytes("c")},
+			},
+			"dequeue": {
+				iThreshold: 4,
+				iEnqueue:   []interface{}{goqueue.Bytes("0"), goqueue.Bytes("1"), goqueue.Bytes("2"), goqueue.Bytes("3")},
+				iDequeue:   3,
+				oItems:     []interface{}{goqueue.Bytes("3")},
+			},
+			"dequeue all": {
+				iThreshold: 2,
+				iEnqueue:   []interface{}{goqueue.Bytes("0"), goqueue.Bytes("1")},
+				iDequeue:   2,
+			},
+		}
+		for cDesc, c := range cases {
+			//trigger compaction with a single kind of operation and re-open
+			// the queue right after, confirm that the operation that
+			// triggered compaction isn't lost
+			path := filepath.Join(t.TempDir(), "queue.log")
+			q, err := persistent.New(path, persistent.WithCompactThreshold(c.iThreshold))
+			if !assert.Nil(t, err, casef, cDesc) {
+				continue
+			}
+			for _, item := range c.iEnqueue {
+				assert.False(t, q.Enqueue(item), casef, cDesc)
+			}
+			for i := 0; i < c.iDequeue; i++ {
+				_, underflow := q.Dequeue()
+				assert.False(t, underflow, casef, cDesc)
+			}
+			q.Close()
+			q, err = persistent.New(path, persistent.WithCompactThreshold(c.iThreshold))
+			if !assert.Nil(t, err, casef, cDesc) {
+				continue
+			}
+			assert.Equal(t, c.oItems, q.Close(), casef, cDesc)
+		}
+	})
+	t.Run("Unsupported Item", func(t *testing.T) {
+		q := newQueue(t)
+		defer q.Close()
+		assert.True(t, q.Enqueue(1.234))
+		itemsRemaining, overflow := q.EnqueueMultiple([]interface{}{goqueue.Bytes("a"), 1.234, []byte("b")})
+		assert.True(t, overflow)
+		assert.Equal(t, []interface{}{1.234, []byte("b")}, itemsRemaining)
+		assert.Equal(t, []interface{}{goqueue.Bytes("a")}, q.Flush())
+	})
+}
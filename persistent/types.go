@@ -0,0 +1,84 @@
+package persistent
+
+import "time"
+
+//SyncPolicy describes how often the log is synced (fsync) to disk, the
+// more often it's synced, the less data that can be lost if the process
+// or the machine crashes
+type SyncPolicy int
+
+const (
+	//SyncEveryOperation will sync the log after every operation
+	SyncEveryOperation SyncPolicy = iota
+
+	//SyncEveryN will sync the log after every N operations
+	SyncEveryN
+
+	//SyncInterval will sync the log periodically
+	SyncInterval
+)
+
+//DefaultCompactThreshold is the default number of records the log can
+// contain before it's considered for compaction
+const DefaultCompactThreshold = 4096
+
+//Syncer can be used to force any operations written to the log to be
+// synced to disk no matter the configured sync policy
+type Syncer interface {
+	Sync() (err error)
+}
+
+//Option can be used to configure a persistent queue on creation
+type Option func(*configuration)
+
+type configuration struct {
+	syncPolicy       SyncPolicy
+	syncN            int
+	syncInterval     time.Duration
+	compactThreshold int
+}
+
+//WithSyncEveryOperation will configure the queue to sync the log after
+// every operation (this is the default)
+func WithSyncEveryOperation() Option {
+	return func(c *configuration) {
+		c.syncPolicy = SyncEveryOperation
+	}
+}
+
+//WithSyncEveryN will configure the queue to sync the log after every n
+// operations, if n is less than one, it will be one
+func WithSyncEveryN(n int) Option {
+	return func(c *configuration) {
+		if n < 1 {
+			n = 1
+		}
+		c.syncPolicy, c.syncN = SyncEveryN, n
+	}
+}
+
+//WithSyncInterval will configure the queue to sync the log periodically
+// at the given interval, if the interval isn't greater than zero it
+// will sync every operation
+func WithSyncInterval(interval time.Duration) Option {
+	return func(c *configuration) {
+		if interval <= 0 {
+			c.syncPolicy = SyncEveryOperation
+			return
+		}
+		c.syncPolicy, c.syncInterval = SyncInterval, interval
+	}
+}
+
+//WithCompactThreshold will configure the number of records the log can
+// contain before it's considered for compaction; the log is compacted
+// (re-written with only the items in the queue) when it contains more
+// than the threshold and more than twice the number of items in the queue
+func WithCompactThreshold(n int) Option {
+	return func(c *configuration) {
+		if n < 1 {
+			n = 1
+		}
+		c.compactThreshold = n
+	}
+}
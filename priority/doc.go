@@ -0,0 +1,10 @@
+// Copyright 2022 antonio-alexander. All rights reserved.
+// Use of this source code is governed by an MIT
+// license that can be found in the LICENSE file.
+
+/*
+	Package priority provides an un-bounded queue implementation where items
+	with a higher priority are dequeued first and items with the same
+	priority are dequeued in the order they were enqueued
+*/
+package priority
@@ -0,0 +1,120 @@
+package priority
+
+type element[T any] struct {
+	item     T
+	priority int
+	sequence uint64
+}
+
+//heap is a binary max-heap of elements ordered by priority and then by
+// sequence such that elements with the same priority are popped in the
+// order they were pushed
+type heap[T any] struct {
+	data     []element[T]
+	sequence uint64
+}
+
+func (h *heap[T]) less(i, j int) bool {
+	if h.data[i].priority != h.data[j].priority {
+		return h.data[i].priority > h.data[j].priority
+	}
+	return h.data[i].sequence < h.data[j].sequence
+}
+
+func (h *heap[T]) swap(i, j int) {
+	h.data[i], h.data[j] = h.data[j], h.data[i]
+}
+
+func (h *heap[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(i, parent) {
+			return
+		}
+		h.swap(i, parent)
+		i = parent
+	}
+}
+
+func (h *heap[T]) down(i int) {
+	for {
+		left, right, first := 2*i+1, 2*i+2, i
+		if left < len(h.data) && h.less(left, first) {
+			first = left
+		}
+		if right < len(h.data) && h.less(right, first) {
+			first = right
+		}
+		if first == i {
+			return
+		}
+		h.swap(i, first)
+		i = first
+	}
+}
+
+func (h *heap[T]) size() int {
+	return len(h.data)
+}
+
+func (h *heap[T]) push(item T, priority int) {
+	h.data = append(h.data, element[T]{
+		item:     item,
+		priority: priority,
+		sequence: h.sequence,
+	})
+	h.sequence++
+	h.up(len(h.data) - 1)
+}
+
+func (h *heap[T]) pop() (item T, underflow bool) {
+	var empty element[T]
+
+	if len(h.data) <= 0 {
+		return item, true
+	}
+	last := len(h.data) - 1
+	item = h.data[0].item
+	h.swap(0, last)
+	h.data[last] = empty
+	h.data = h.data[:last]
+	h.down(0)
+	return item, false
+}
+
+func (h *heap[T]) popMultiple(n int) (items []T) {
+	if n > len(h.data) {
+		n = len(h.data)
+	}
+	if n <= 0 {
+		return
+	}
+	items = make([]T, 0, n)
+	for i := 0; i < n; i++ {
+		item, _ := h.pop()
+		items = append(items, item)
+	}
+	return
+}
+
+//peek will return up to n items in the order they would be dequeued
+// without modifying the heap, it pops from a copy
+func (h *heap[T]) peek(n int) (items []T) {
+	if n > len(h.data) {
+		n = len(h.data)
+	}
+	if n <= 0 {
+		return
+	}
+	c := &heap[T]{data: make([]element[T], len(h.data))}
+	copy(c.data, h.data)
+	return c.popMultiple(n)
+}
+
+//compact will copy the heap into a slice that's no larger than the
+// number of elements
+func (h *heap[T]) compact() {
+	data := make([]element[T], len(h.data))
+	copy(data, h.data)
+	h.data = data
+}
@@ -0,0 +1,175 @@
+package priority
+
+import (
+	"sync"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+	internal "github.com/antonio-alexander/go-queue/internal"
+)
+
+type queuePriority[T any] struct {
+	sync.RWMutex
+	signalIn  chan struct{}
+	signalOut chan struct{}
+	closed    bool
+	data      heap[T]
+}
+
+//New can be used to create an un-bounded priority queue of empty
+// interface, items enqueued without a priority will be given the
+// DefaultPriority
+func New() interface {
+	goqueue.Owner
+	goqueue.GarbageCollecter
+	goqueue.Dequeuer
+	goqueue.Enqueuer
+	goqueue.Length
+	goqueue.Event
+	goqueue.Peeker
+	EnqueueWithPriority
+} {
+	return NewOf[interface{}]()
+}
+
+//NewOf can be used to create a type-safe un-bounded priority queue of T,
+// items enqueued without a priority will be given the DefaultPriority
+func NewOf[T any]() interface {
+	goqueue.OwnerOf[T]
+	goqueue.GarbageCollecter
+	goqueue.DequeuerOf[T]
+	goqueue.EnqueuerOf[T]
+	goqueue.Length
+	goqueue.Event
+	goqueue.PeekerOf[T]
+	EnqueueWithPriorityOf[T]
+} {
+	return &queuePriority[T]{
+		signalIn:  make(chan struct{}, 1),
+		signalOut: make(chan struct{}, 1),
+	}
+}
+
+func (q *queuePriority[T]) enqueue(item T, priority int) (overflow bool) {
+	if q.closed {
+		return true
+	}
+	q.data.push(item, priority)
+	internal.SendSignal(q.signalIn)
+	return false
+}
+
+func (q *queuePriority[T]) dequeueMultiple(n int) (items []T) {
+	if items = q.data.popMultiple(n); len(items) > 0 {
+		internal.SendSignal(q.signalOut)
+	}
+	return
+}
+
+func (q *queuePriority[T]) Close() (remainingElements []T) {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.closed {
+		return
+	}
+	remainingElements = q.data.popMultiple(q.data.size())
+	close(q.signalIn)
+	close(q.signalOut)
+	q.data, q.closed = heap[T]{}, true
+
+	return
+}
+
+func (q *queuePriority[T]) GarbageCollect() {
+	q.Lock()
+	defer q.Unlock()
+	q.data.compact()
+}
+
+func (q *queuePriority[T]) GetSignalIn() (signal <-chan struct{}) {
+	q.RLock()
+	defer q.RUnlock()
+	return q.signalIn
+}
+
+func (q *queuePriority[T]) GetSignalOut() (signal <-chan struct{}) {
+	q.RLock()
+	defer q.RUnlock()
+	return q.signalOut
+}
+
+func (q *queuePriority[T]) Dequeue() (item T, underflow bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	if item, underflow = q.data.pop(); !underflow {
+		internal.SendSignal(q.signalOut)
+	}
+
+	return
+}
+
+func (q *queuePriority[T]) DequeueMultiple(n int) (items []T) {
+	q.Lock()
+	defer q.Unlock()
+	return q.dequeueMultiple(n)
+}
+
+func (q *queuePriority[T]) Flush() (items []T) {
+	q.Lock()
+	defer q.Unlock()
+	return q.dequeueMultiple(q.data.size())
+}
+
+func (q *queuePriority[T]) Enqueue(item T) (overflow bool) {
+	q.Lock()
+	defer q.Unlock()
+	return q.enqueue(item, DefaultPriority)
+}
+
+func (q *queuePriority[T]) EnqueueMultiple(items []T) (remainingElements []T, overflow bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	for i, item := range items {
+		if overflow = q.enqueue(item, DefaultPriority); overflow {
+			return items[i:], true
+		}
+	}
+
+	return
+}
+
+func (q *queuePriority[T]) EnqueueWithPriority(item T, priority int) (overflow bool) {
+	q.Lock()
+	defer q.Unlock()
+	return q.enqueue(item, priority)
+}
+
+func (q *queuePriority[T]) Length() (size int) {
+	q.RLock()
+	defer q.RUnlock()
+	return q.data.size()
+}
+
+func (q *queuePriority[T]) Peek() (items []T) {
+	q.RLock()
+	defer q.RUnlock()
+	return q.data.peek(q.data.size())
+}
+
+func (q *queuePriority[T]) PeekHead() (item T, underflow bool) {
+	q.RLock()
+	defer q.RUnlock()
+
+	if q.data.size() <= 0 {
+		return item, true
+	}
+	return q.data.data[0].item, false
+}
+
+func (q *queuePriority[T]) PeekFromHead(n int) (items []T) {
+	q.RLock()
+	defer q.RUnlock()
+	return q.data.peek(n)
+}
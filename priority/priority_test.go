@@ -0,0 +1,247 @@
+package priority_test
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+	infinite_tests "github.com/antonio-alexander/go-queue/infinite/tests"
+	priority "github.com/antonio-alexander/go-queue/priority"
+	goqueue_tests "github.com/antonio-alexander/go-queue/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	mustTimeout = time.Second
+	mustRate    = time.Millisecond
+)
+
+func init() {
+	rand.Seed(int64(time.Now().Nanosecond()))
+}
+
+func TestQueue(t *testing.T) {
+	t.Run("Test Garbage Collect", goqueue_tests.TestGarbageCollect(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Owner
+		goqueue.GarbageCollecter
+	} {
+		return priority.New()
+	}))
+	t.Run("Test Dequeue", goqueue_tests.TestDequeue(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return priority.New()
+	}))
+	t.Run("Test Dequeue Event", goqueue_tests.TestDequeueEvent(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Event
+	} {
+		return priority.New()
+	}))
+	t.Run("Test Dequeue Multiple", goqueue_tests.TestDequeueMultiple(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return priority.New()
+	}))
+	t.Run("Test Flush", goqueue_tests.TestFlush(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return priority.New()
+	}))
+	t.Run("Test Peek", goqueue_tests.TestPeek(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Peeker
+	} {
+		return priority.New()
+	}))
+	t.Run("Test Peek From Head", goqueue_tests.TestPeekFromHead(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Peeker
+	} {
+		return priority.New()
+	}))
+	t.Run("Test Event", goqueue_tests.TestEvent(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Event
+	} {
+		return priority.New()
+	}))
+	t.Run("Test Length", goqueue_tests.TestLength(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Length
+	} {
+		return priority.New()
+	}))
+	t.Run("Test Queue", goqueue_tests.TestQueue(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return priority.New()
+	}))
+	t.Run("Test Asynchronous", goqueue_tests.TestAsync(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return priority.New()
+	}))
+	t.Run("Test Queue Of", goqueue_tests.TestQueueOf(t, func(size int) interface {
+		goqueue.OwnerOf[*goqueue.Example]
+		goqueue.EnqueuerOf[*goqueue.Example]
+		goqueue.DequeuerOf[*goqueue.Example]
+		goqueue.PeekerOf[*goqueue.Example]
+	} {
+		return priority.NewOf[*goqueue.Example]()
+	}))
+	t.Run("Test Enqueue", infinite_tests.TestEnqueue(t, mustRate, mustTimeout, func() interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return priority.New()
+	}))
+	t.Run("Test Enqueue Multiple", infinite_tests.TestEnqueueMultiple(t, mustRate, mustTimeout, func() interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return priority.New()
+	}))
+	t.Run("Test Enqueue Event", infinite_tests.TestEnqueueEvent(t, mustRate, mustTimeout, func() interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Event
+	} {
+		return priority.New()
+	}))
+}
+
+func TestPriorityQueue(t *testing.T) {
+	t.Run("Order", func(t *testing.T) {
+		cases := map[string]struct {
+			iItems      []int
+			iPriorities []int
+			oItems      []int
+		}{
+			"default_priority_is_fifo": {
+				iItems:      []int{1, 2, 3, 4, 5},
+				iPriorities: []int{0, 0, 0, 0, 0},
+				oItems:      []int{1, 2, 3, 4, 5},
+			},
+			"highest_priority_first": {
+				iItems:      []int{1, 2, 3, 4},
+				iPriorities: []int{1, 4, 2, 3},
+				oItems:      []int{2, 4, 3, 1},
+			},
+			"fifo_within_priority": {
+				iItems:      []int{1, 2, 3, 4, 5, 6},
+				iPriorities: []int{1, 2, 1, 2, 1, -1},
+				oItems:      []int{2, 4, 1, 3, 5, 6},
+			},
+		}
+		for cDesc, c := range cases {
+			q := priority.NewOf[int]()
+			for i, item := range c.iItems {
+				overflow := q.EnqueueWithPriority(item, c.iPriorities[i])
+				assert.False(t, overflow, "case: %s", cDesc)
+			}
+			assert.Equal(t, c.oItems, q.Peek(), "case: %s", cDesc)
+			assert.Equal(t, c.oItems[:2], q.PeekFromHead(2), "case: %s", cDesc)
+			item, underflow := q.PeekHead()
+			assert.False(t, underflow, "case: %s", cDesc)
+			assert.Equal(t, c.oItems[0], item, "case: %s", cDesc)
+			assert.Equal(t, c.oItems, q.Flush(), "case: %s", cDesc)
+			q.Close()
+		}
+	})
+	t.Run("Fifo Within Priority", func(t *testing.T) {
+		//KIM: this is done with enough items that the heap has to
+		// re-order itself many times over
+		const nItems, nPriorities = 1000, 5
+
+		q := priority.NewOf[int]()
+		defer q.Close()
+		priorities := make([]int, nItems)
+		for i := 0; i < nItems; i++ {
+			priorities[i] = rand.Intn(nPriorities)
+			q.EnqueueWithPriority(i, priorities[i])
+		}
+		items := q.Flush()
+		assert.Len(t, items, nItems)
+		last := map[int]int{}
+		for i, item := range items {
+			if i > 0 {
+				assert.GreaterOrEqual(t, priorities[items[i-1]], priorities[item])
+			}
+			if previous, ok := last[priorities[item]]; ok {
+				assert.Greater(t, item, previous)
+			}
+			last[priorities[item]] = item
+		}
+	})
+	t.Run("Default Priority", func(t *testing.T) {
+		q := priority.New()
+		defer q.Close()
+		q.EnqueueWithPriority(1, priority.DefaultPriority-1)
+		q.Enqueue(2)
+		q.EnqueueWithPriority(3, priority.DefaultPriority+1)
+		q.Enqueue(4)
+		assert.Equal(t, []interface{}{3, 2, 4, 1}, q.Flush())
+	})
+	t.Run("Closed", func(t *testing.T) {
+		q := priority.New()
+		q.EnqueueWithPriority(1, 1)
+		q.EnqueueWithPriority(2, 2)
+		assert.Equal(t, []interface{}{2, 1}, q.Close())
+		assert.True(t, q.Enqueue(3))
+		assert.True(t, q.EnqueueWithPriority(3, 1))
+		assert.Nil(t, q.Close())
+	})
+	t.Run("Concurrent", func(t *testing.T) {
+		const nProducers, nItems = 4, 250
+
+		var wg sync.WaitGroup
+
+		q := priority.NewOf[int]()
+		defer q.Close()
+		for i := 0; i < nProducers; i++ {
+			wg.Add(1)
+			go func(producer int) {
+				defer wg.Done()
+				for j := 0; j < nItems; j++ {
+					q.EnqueueWithPriority(producer*nItems+j, producer)
+				}
+			}(i)
+		}
+		wg.Wait()
+		items := q.Flush()
+		if !assert.Len(t, items, nProducers*nItems) {
+			return
+		}
+		for i, item := range items {
+			assert.Equal(t, (nProducers-1-i/nItems)*nItems+i%nItems, item)
+		}
+	})
+}
@@ -0,0 +1,15 @@
+package priority
+
+//DefaultPriority is the priority given to items that are enqueued without
+// a priority (e.g. via Enqueue() or EnqueueMultiple())
+const DefaultPriority int = 0
+
+//EnqueueWithPriorityOf can be used to enqueue an item of T with the given
+// priority, the higher the priority, the sooner it will be dequeued
+type EnqueueWithPriorityOf[T any] interface {
+	EnqueueWithPriority(item T, priority int) (overflow bool)
+}
+
+//EnqueueWithPriority can be used to enqueue an item with the given
+// priority, the higher the priority, the sooner it will be dequeued
+type EnqueueWithPriority = EnqueueWithPriorityOf[interface{}]
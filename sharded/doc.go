@@ -0,0 +1,10 @@
+// Copyright 2022 antonio-alexander. All rights reserved.
+// Use of this source code is governed by an MIT
+// license that can be found in the LICENSE file.
+
+/*
+	Package sharded provides a queue implementation that spreads items across
+	multiple internal queues (shards) to reduce contention when strict FIFO
+	order isn't required
+*/
+package sharded
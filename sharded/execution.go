@@ -0,0 +1,10 @@
+package sharded
+
+import "hash/fnv"
+
+//hash will return the FNV-1a hash of the given key
+func hash(key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return h.Sum64()
+}
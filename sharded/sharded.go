@@ -0,0 +1,285 @@
+package sharded
+
+import (
+	"sync"
+	"sync/atomic"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+	internal "github.com/antonio-alexander/go-queue/internal"
+)
+
+type queueSharded struct {
+	//KIM: the counters must be first so they're 64-bit aligned for
+	// atomic operations on 32-bit platforms
+	enqueueNext uint64
+	dequeueNext uint64
+	sync.RWMutex
+	configuration
+	closed    bool
+	shards    []Shard
+	signalIn  chan struct{}
+	signalOut chan struct{}
+}
+
+//New can be used to create a sharded queue with n shards, each shard is
+// created by calling newShard, if n is less than one, it will be one. Items
+// are placed into the shards round-robin (or by the hash of their key if
+// configured) and dequeued round-robin; if a shard is empty, items will be
+// taken from the other shards such that dequeue only underflows if all of
+// the shards are empty
+func New(n int, newShard func() Shard, options ...Option) interface {
+	goqueue.Owner
+	goqueue.GarbageCollecter
+	goqueue.Dequeuer
+	goqueue.Enqueuer
+	goqueue.EnqueueInFronter
+	goqueue.Length
+	goqueue.Event
+	goqueue.Peeker
+	ShardCounter
+} {
+	if n < 1 {
+		n = 1
+	}
+	q := &queueSharded{
+		shards:    make([]Shard, 0, n),
+		signalIn:  make(chan struct{}, 1),
+		signalOut: make(chan struct{}, 1),
+	}
+	for i := 0; i < n; i++ {
+		q.shards = append(q.shards, newShard())
+	}
+	for _, option := range options {
+		option(&q.configuration)
+	}
+	return q
+}
+
+//enqueue will place the item into the shard for its key, if there's no
+// key function, it will place the item into the next shard and attempt
+// the remaining shards if that shard is full; it expects the queue to be
+// read locked
+func (q *queueSharded) enqueue(item interface{}, enqueue func(shard Shard, item interface{}) (overflow bool)) (overflow bool) {
+	n := uint64(len(q.shards))
+	if q.keyFunc != nil {
+		return enqueue(q.shards[hash(q.keyFunc(item))%n], item)
+	}
+	next := atomic.AddUint64(&q.enqueueNext, 1) - 1
+	for i := uint64(0); i < n; i++ {
+		if overflow = enqueue(q.shards[(next+i)%n], item); !overflow {
+			return
+		}
+	}
+	return
+}
+
+//dequeueMultiple will dequeue up to n items starting with the next shard,
+// if the shard doesn't have enough items, the remaining items will be
+// taken (stolen) from the other shards; it expects the queue to be read
+// locked
+func (q *queueSharded) dequeueMultiple(n int) (items []interface{}) {
+	nShards := uint64(len(q.shards))
+	next := atomic.AddUint64(&q.dequeueNext, 1) - 1
+	for i := uint64(0); i < nShards && len(items) < n; i++ {
+		items = append(items, q.shards[(next+i)%nShards].DequeueMultiple(n-len(items))...)
+	}
+	if len(items) > 0 {
+		internal.SendSignal(q.signalOut)
+	}
+	return
+}
+
+//peekFromHead will peek up to n items starting with the shard that will
+// be dequeued from next, it expects the queue to be read locked
+func (q *queueSharded) peekFromHead(n int) (items []interface{}) {
+	nShards := uint64(len(q.shards))
+	next := atomic.LoadUint64(&q.dequeueNext)
+	for i := uint64(0); i < nShards && len(items) < n; i++ {
+		items = append(items, q.shards[(next+i)%nShards].PeekFromHead(n-len(items))...)
+	}
+	return
+}
+
+func (q *queueSharded) length() (size int) {
+	for _, shard := range q.shards {
+		size += shard.Length()
+	}
+	return
+}
+
+//Close will close all of the shards and return their remaining items in
+// shard order
+func (q *queueSharded) Close() (remainingElements []interface{}) {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.closed {
+		return
+	}
+	for _, shard := range q.shards {
+		remainingElements = append(remainingElements, shard.Close()...)
+	}
+	close(q.signalIn)
+	close(q.signalOut)
+	q.closed = true
+
+	return
+}
+
+func (q *queueSharded) GarbageCollect() {
+	q.RLock()
+	defer q.RUnlock()
+
+	if q.closed {
+		return
+	}
+	for _, shard := range q.shards {
+		shard.GarbageCollect()
+	}
+}
+
+func (q *queueSharded) ShardCount() (n int) {
+	return len(q.shards)
+}
+
+func (q *queueSharded) GetSignalIn() (signal <-chan struct{}) {
+	return q.signalIn
+}
+
+func (q *queueSharded) GetSignalOut() (signal <-chan struct{}) {
+	return q.signalOut
+}
+
+func (q *queueSharded) Dequeue() (item interface{}, underflow bool) {
+	q.RLock()
+	defer q.RUnlock()
+
+	if q.closed {
+		return nil, true
+	}
+	items := q.dequeueMultiple(1)
+	if len(items) <= 0 {
+		return nil, true
+	}
+	return items[0], false
+}
+
+func (q *queueSharded) DequeueMultiple(n int) (items []interface{}) {
+	q.RLock()
+	defer q.RUnlock()
+
+	if q.closed {
+		return
+	}
+	return q.dequeueMultiple(n)
+}
+
+//Flush will remove all of the items from all of the shards, items are
+// returned in shard order
+func (q *queueSharded) Flush() (items []interface{}) {
+	q.RLock()
+	defer q.RUnlock()
+
+	if q.closed {
+		return
+	}
+	for _, shard := range q.shards {
+		items = append(items, shard.Flush()...)
+	}
+	if len(items) > 0 {
+		internal.SendSignal(q.signalOut)
+	}
+	return
+}
+
+func (q *queueSharded) Enqueue(item interface{}) (overflow bool) {
+	q.RLock()
+	defer q.RUnlock()
+
+	if q.closed {
+		return true
+	}
+	if overflow = q.enqueue(item, Shard.Enqueue); !overflow {
+		internal.SendSignal(q.signalIn)
+	}
+	return
+}
+
+func (q *queueSharded) EnqueueMultiple(items []interface{}) (remainingElements []interface{}, overflow bool) {
+	q.RLock()
+	defer q.RUnlock()
+
+	if q.closed {
+		return items, true
+	}
+	for i, item := range items {
+		if overflow = q.enqueue(item, Shard.Enqueue); overflow {
+			remainingElements = items[i:]
+			break
+		}
+	}
+	if len(remainingElements) < len(items) {
+		internal.SendSignal(q.signalIn)
+	}
+	return
+}
+
+//EnqueueInFront will place the item in front of the shard it would've
+// been enqueued into, keep in mind that with more than one shard the
+// item isn't necessarily the next item to be dequeued
+func (q *queueSharded) EnqueueInFront(item interface{}) (overflow bool) {
+	q.RLock()
+	defer q.RUnlock()
+
+	if q.closed {
+		return true
+	}
+	if overflow = q.enqueue(item, Shard.EnqueueInFront); !overflow {
+		internal.SendSignal(q.signalIn)
+	}
+	return
+}
+
+func (q *queueSharded) Length() (size int) {
+	q.RLock()
+	defer q.RUnlock()
+
+	if q.closed {
+		return 0
+	}
+	return q.length()
+}
+
+func (q *queueSharded) Peek() (items []interface{}) {
+	q.RLock()
+	defer q.RUnlock()
+
+	if q.closed {
+		return
+	}
+	return q.peekFromHead(q.length())
+}
+
+func (q *queueSharded) PeekHead() (item interface{}, underflow bool) {
+	q.RLock()
+	defer q.RUnlock()
+
+	if q.closed {
+		return nil, true
+	}
+	items := q.peekFromHead(1)
+	if len(items) <= 0 {
+		return nil, true
+	}
+	return items[0], false
+}
+
+func (q *queueSharded) PeekFromHead(n int) (items []interface{}) {
+	q.RLock()
+	defer q.RUnlock()
+
+	if q.closed {
+		return
+	}
+	return q.peekFromHead(n)
+}
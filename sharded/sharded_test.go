@@ -0,0 +1,326 @@
+package sharded_test
+
+import (
+	"math/rand"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+	finite "github.com/antonio-alexander/go-queue/finite"
+	infinite "github.com/antonio-alexander/go-queue/infinite"
+	sharded "github.com/antonio-alexander/go-queue/sharded"
+	goqueue_tests "github.com/antonio-alexander/go-queue/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	mustTimeout = time.Second
+	mustRate    = time.Millisecond
+	casef       = "case: %s"
+)
+
+func init() {
+	rand.Seed(int64(time.Now().Nanosecond()))
+}
+
+func newFinite(size int) func() sharded.Shard {
+	return func() sharded.Shard {
+		return finite.New(size)
+	}
+}
+
+func TestShardedQueue(t *testing.T) {
+	t.Run("Test Round Robin", func(t *testing.T) {
+		const nShards, size = 4, 2
+
+		//enqueue until every shard is full and confirm that items are
+		// dequeued in the order they were enqueued since both enqueue
+		// and dequeue move to the next shard
+		q := sharded.New(nShards, newFinite(size))
+		defer q.Close()
+		assert.Equal(t, nShards, q.ShardCount())
+		for i := 0; i < nShards*size; i++ {
+			assert.False(t, q.Enqueue(i))
+		}
+		assert.True(t, q.Enqueue(nShards*size))
+		assert.Equal(t, nShards*size, q.Length())
+		for i := 0; i < nShards*size; i++ {
+			item, underflow := q.Dequeue()
+			assert.False(t, underflow)
+			assert.Equal(t, i, item)
+		}
+		_, underflow := q.Dequeue()
+		assert.True(t, underflow)
+	})
+	t.Run("Test Key Hash", func(t *testing.T) {
+		const nShards, size = 4, 100
+
+		//enqueue items with a handful of keys and confirm that items
+		// with the same key are dequeued in the order they were enqueued
+		q := sharded.New(nShards, newFinite(size), sharded.WithKeyFunc(func(item interface{}) string {
+			return strconv.Itoa(item.(int) % 3)
+		}))
+		defer q.Close()
+		for i := 0; i < 30; i++ {
+			assert.False(t, q.Enqueue(i))
+		}
+		last := map[int]int{}
+		for _, item := range q.Flush() {
+			key := item.(int) % 3
+			if previous, ok := last[key]; ok {
+				assert.Greater(t, item.(int), previous)
+			}
+			last[key] = item.(int)
+		}
+		assert.Len(t, last, 3)
+
+		//confirm that items with the same key overflow once their shard
+		// is full even though other shards have room
+		q = sharded.New(nShards, newFinite(1), sharded.WithKeyFunc(func(interface{}) string {
+			return "key"
+		}))
+		defer q.Close()
+		assert.False(t, q.Enqueue(1))
+		assert.True(t, q.Enqueue(2))
+		remaining, overflow := q.EnqueueMultiple([]interface{}{3, 4})
+		assert.True(t, overflow)
+		assert.Equal(t, []interface{}{3, 4}, remaining)
+		assert.Equal(t, 1, q.Length())
+	})
+	t.Run("Test Work Stealing", func(t *testing.T) {
+		const nShards = 4
+
+		//place all of the items in a single shard and confirm that
+		// every dequeue is successful no matter which shard is next
+		q := sharded.New(nShards, newFinite(nShards), sharded.WithKeyFunc(func(interface{}) string {
+			return "key"
+		}))
+		defer q.Close()
+		for i := 0; i < nShards; i++ {
+			assert.False(t, q.Enqueue(i))
+		}
+		item, underflow := q.PeekHead()
+		assert.False(t, underflow)
+		assert.Equal(t, 0, item)
+		for i := 0; i < nShards; i++ {
+			item, underflow := q.Dequeue()
+			assert.False(t, underflow)
+			assert.Equal(t, i, item)
+		}
+		assert.Empty(t, q.DequeueMultiple(1))
+	})
+	t.Run("Test Multiple", func(t *testing.T) {
+		cases := map[string]struct {
+			iShards   int
+			iSize     int
+			iItems    []interface{}
+			iN        int
+			oItems    int
+			oRemained []interface{}
+		}{
+			"fits": {
+				iShards: 3,
+				iSize:   2,
+				iItems:  []interface{}{1, 2, 3, 4},
+				iN:      4,
+				oItems:  4,
+			},
+			"overflow": {
+				iShards:   2,
+				iSize:     1,
+				iItems:    []interface{}{1, 2, 3},
+				iN:        3,
+				oItems:    2,
+				oRemained: []interface{}{3},
+			},
+			"partial": {
+				iShards: 4,
+				iSize:   4,
+				iItems:  []interface{}{1, 2, 3, 4, 5, 6},
+				iN:      2,
+				oItems:  2,
+			},
+		}
+		for cDesc, c := range cases {
+			q := sharded.New(c.iShards, newFinite(c.iSize))
+			remaining, _ := q.EnqueueMultiple(c.iItems)
+			assert.Equal(t, c.oRemained, remaining, casef, cDesc)
+			assert.Len(t, q.Peek(), len(c.iItems)-len(c.oRemained), casef, cDesc)
+			assert.Len(t, q.PeekFromHead(c.iN), c.oItems, casef, cDesc)
+			assert.Len(t, q.DequeueMultiple(c.iN), c.oItems, casef, cDesc)
+			assert.Len(t, q.Close(), len(c.iItems)-len(c.oRemained)-c.oItems, casef, cDesc)
+		}
+	})
+	t.Run("Test Event", func(t *testing.T) {
+		//confirm that enqueuing into (and dequeuing from) any of the
+		// shards sends a signal
+		q := sharded.New(2, newFinite(2))
+		signalIn, signalOut := q.GetSignalIn(), q.GetSignalOut()
+		for i := 0; i < 2; i++ {
+			assert.False(t, q.Enqueue(i))
+			select {
+			case <-time.After(mustTimeout):
+				assert.Fail(t, "no signal received when expected")
+			case <-signalIn:
+			}
+		}
+		for i := 0; i < 2; i++ {
+			_, underflow := q.Dequeue()
+			assert.False(t, underflow)
+			select {
+			case <-time.After(mustTimeout):
+				assert.Fail(t, "no signal received when expected")
+			case <-signalOut:
+			}
+		}
+
+		//confirm that the signals are closed when the queue is closed
+		q.Close()
+		_, ok := <-signalIn
+		assert.False(t, ok)
+		_, ok = <-signalOut
+		assert.False(t, ok)
+		assert.Empty(t, q.Close())
+		assert.True(t, q.Enqueue(1))
+	})
+	t.Run("Test Producers Consumers", func(t *testing.T) {
+		const nProducers, nConsumers, nItems, nShards = 4, 4, 1000, 4
+
+		var wg sync.WaitGroup
+
+		//confirm that every item is received exactly once
+		q := sharded.New(nShards, newFinite(8))
+		defer q.Close()
+		received := make(chan []int, nConsumers)
+		for i := 0; i < nProducers; i++ {
+			wg.Add(1)
+			go func(producer int) {
+				defer wg.Done()
+				for i := 0; i < nItems; {
+					if overflow := q.Enqueue(producer*nItems + i); overflow {
+						runtime.Gosched()
+						continue
+					}
+					i++
+				}
+			}(i)
+		}
+		stopper := make(chan struct{})
+		for i := 0; i < nConsumers; i++ {
+			go func() {
+				var items []int
+
+				defer func() { received <- items }()
+				for {
+					item, underflow := q.Dequeue()
+					if !underflow {
+						items = append(items, item.(int))
+						continue
+					}
+					select {
+					case <-stopper:
+						for _, item := range q.Flush() {
+							items = append(items, item.(int))
+						}
+						return
+					default:
+						runtime.Gosched()
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		close(stopper)
+		var items []int
+		for i := 0; i < nConsumers; i++ {
+			items = append(items, <-received...)
+		}
+		sort.Ints(items)
+		if assert.Len(t, items, nProducers*nItems) {
+			for i, item := range items {
+				if !assert.Equal(t, i, item) {
+					break
+				}
+			}
+		}
+	})
+}
+
+func TestQueue(t *testing.T) {
+	//KIM: with a single shard the sharded queue maintains order so the
+	// existing test suites can be used as-is
+	t.Run("Test Dequeue", goqueue_tests.TestDequeue(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return sharded.New(1, newFinite(size))
+	}))
+	t.Run("Test Dequeue Multiple", goqueue_tests.TestDequeueMultiple(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return sharded.New(1, newFinite(size))
+	}))
+	t.Run("Test Flush", goqueue_tests.TestFlush(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return sharded.New(1, newFinite(size))
+	}))
+	t.Run("Test Peek", goqueue_tests.TestPeek(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Peeker
+	} {
+		return sharded.New(1, newFinite(size))
+	}))
+	t.Run("Test Peek From Head", goqueue_tests.TestPeekFromHead(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Peeker
+	} {
+		return sharded.New(1, newFinite(size))
+	}))
+	t.Run("Test Event", goqueue_tests.TestEvent(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Event
+	} {
+		return sharded.New(4, newFinite(size))
+	}))
+	t.Run("Test Length", goqueue_tests.TestLength(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Length
+	} {
+		return sharded.New(4, newFinite(size))
+	}))
+	t.Run("Test Queue", goqueue_tests.TestQueue(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return sharded.New(1, func() sharded.Shard {
+			return infinite.New(size)
+		})
+	}))
+	t.Run("Test Asynchronous", goqueue_tests.TestAsync(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return sharded.New(4, newFinite(size))
+	}))
+}
@@ -0,0 +1,43 @@
+package sharded
+
+import (
+	goqueue "github.com/antonio-alexander/go-queue"
+)
+
+//Shard describes the queues the sharded queue is built on top of, both
+// the finite and infinite queues satisfy this interface
+type Shard interface {
+	goqueue.Owner
+	goqueue.GarbageCollecter
+	goqueue.Dequeuer
+	goqueue.Enqueuer
+	goqueue.EnqueueInFronter
+	goqueue.Length
+	goqueue.Peeker
+}
+
+//ShardCounter can be used to determine the number of shards items are
+// spread across
+type ShardCounter interface {
+	ShardCount() (n int)
+}
+
+//KeyFunc can be used to determine the key of an item, items with the same
+// key are always placed in the same shard
+type KeyFunc func(item interface{}) (key string)
+
+//Option can be used to configure a sharded queue on creation
+type Option func(*configuration)
+
+type configuration struct {
+	keyFunc KeyFunc
+}
+
+//WithKeyFunc will configure the sharded queue to place items in the shard
+// given by the hash of their key rather than round-robin, items with the
+// same key will be dequeued in the order they were enqueued
+func WithKeyFunc(keyFunc KeyFunc) Option {
+	return func(c *configuration) {
+		c.keyFunc = keyFunc
+	}
+}
@@ -0,0 +1,9 @@
+// Copyright 2022 antonio-alexander. All rights reserved.
+// Use of this source code is governed by an MIT
+// license that can be found in the LICENSE file.
+
+/*
+	Package spsc provides a bounded, lock-free queue implementation for a
+	single producer and a single consumer
+*/
+package spsc
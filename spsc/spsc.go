@@ -0,0 +1,183 @@
+package spsc
+
+import (
+	"sync/atomic"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+	finite "github.com/antonio-alexander/go-queue/finite"
+	internal "github.com/antonio-alexander/go-queue/internal"
+)
+
+//cacheLinePad is used to keep the indexes written by the producer and the
+// consumer on separate cache lines so they don't invalidate each other
+type cacheLinePad [56]byte
+
+type queueSPSC[T any] struct {
+	//KIM: head and tail must be first so they're 64-bit aligned for
+	// atomic operations on 32-bit platforms
+	head      uint64 //only written by the consumer
+	_         cacheLinePad
+	tail      uint64 //only written by the producer
+	_         cacheLinePad
+	closed    uint32
+	data      []T
+	signalIn  chan struct{}
+	signalOut chan struct{}
+}
+
+//New can be used to create a single producer, single consumer queue of empty
+// interface with the given size, if size is less than one, it will be one.
+// Enqueue() and EnqueueMultiple() must only be called by one goroutine (the
+// producer) and Dequeue(), DequeueMultiple() and Flush() must only be called
+// by one goroutine (the consumer), Close() dequeues on behalf of the consumer
+// so it should only be called once the producer has stopped and either by
+// the consumer or once the consumer has stopped
+func New(size int) interface {
+	goqueue.Owner
+	goqueue.Dequeuer
+	goqueue.Enqueuer
+	goqueue.Length
+	goqueue.Event
+	finite.Capacity
+} {
+	return NewOf[interface{}](size)
+}
+
+//NewOf can be used to create a type-safe single producer, single consumer
+// queue of T with the given size, if size is less than one, it will be one
+func NewOf[T any](size int) interface {
+	goqueue.OwnerOf[T]
+	goqueue.DequeuerOf[T]
+	goqueue.EnqueuerOf[T]
+	goqueue.Length
+	goqueue.Event
+	finite.Capacity
+} {
+	if size < 1 {
+		size = 1
+	}
+	return &queueSPSC[T]{
+		data:      make([]T, size),
+		signalIn:  make(chan struct{}, size),
+		signalOut: make(chan struct{}, size),
+	}
+}
+
+//enqueueMultiple will copy as many items as will fit into the ring and then
+// publish them all at once by moving the tail, it should only be called by
+// the producer
+func (q *queueSPSC[T]) enqueueMultiple(items []T) (n int) {
+	if atomic.LoadUint32(&q.closed) != 0 {
+		return 0
+	}
+	tail, head := atomic.LoadUint64(&q.tail), atomic.LoadUint64(&q.head)
+	capacity := uint64(len(q.data))
+	if n = int(capacity - (tail - head)); n > len(items) {
+		n = len(items)
+	}
+	if n <= 0 {
+		return 0
+	}
+	for i := 0; i < n; i++ {
+		q.data[(tail+uint64(i))%capacity] = items[i]
+	}
+	atomic.StoreUint64(&q.tail, tail+uint64(n))
+	for i := 0; i < n; i++ {
+		internal.SendSignal(q.signalIn)
+	}
+	return n
+}
+
+//dequeueMultiple will copy up to n items from the ring and then release
+// their slots all at once by moving the head, it should only be called by
+// the consumer
+func (q *queueSPSC[T]) dequeueMultiple(n int) (items []T) {
+	var zero T
+
+	head, tail := atomic.LoadUint64(&q.head), atomic.LoadUint64(&q.tail)
+	capacity := uint64(len(q.data))
+	if available := int(tail - head); n > available {
+		n = available
+	}
+	if n <= 0 {
+		return
+	}
+	items = make([]T, n)
+	for i := 0; i < n; i++ {
+		index := (head + uint64(i)) % capacity
+		items[i], q.data[index] = q.data[index], zero
+	}
+	atomic.StoreUint64(&q.head, head+uint64(n))
+	if atomic.LoadUint32(&q.closed) == 0 {
+		internal.SendSignal(q.signalOut)
+	}
+	return
+}
+
+//Close will mark the queue as closed such that any further enqueues will
+// overflow, it will close the signal channels and return the remaining
+// items; since it dequeues the remaining items (like the consumer) and
+// closes the signal the producer sends on, it should only be called once
+// the producer has stopped and either by the consumer or once the consumer
+// has stopped
+func (q *queueSPSC[T]) Close() (remainingElements []T) {
+	if !atomic.CompareAndSwapUint32(&q.closed, 0, 1) {
+		return
+	}
+	remainingElements = q.dequeueMultiple(len(q.data))
+	close(q.signalIn)
+	close(q.signalOut)
+	return
+}
+
+func (q *queueSPSC[T]) GetSignalIn() (signal <-chan struct{}) {
+	return q.signalIn
+}
+
+func (q *queueSPSC[T]) GetSignalOut() (signal <-chan struct{}) {
+	return q.signalOut
+}
+
+func (q *queueSPSC[T]) Dequeue() (item T, underflow bool) {
+	items := q.dequeueMultiple(1)
+	if len(items) <= 0 {
+		return item, true
+	}
+	return items[0], false
+}
+
+func (q *queueSPSC[T]) DequeueMultiple(n int) (items []T) {
+	return q.dequeueMultiple(n)
+}
+
+func (q *queueSPSC[T]) Flush() (items []T) {
+	return q.dequeueMultiple(len(q.data))
+}
+
+func (q *queueSPSC[T]) Enqueue(item T) (overflow bool) {
+	return q.enqueueMultiple([]T{item}) <= 0
+}
+
+func (q *queueSPSC[T]) EnqueueMultiple(items []T) (remainingElements []T, overflow bool) {
+	if n := q.enqueueMultiple(items); n < len(items) {
+		return items[n:], true
+	}
+	return
+}
+
+//Length will return the number of items in the queue, if the producer or
+// consumer are active, it's a snapshot that may already be out of date
+func (q *queueSPSC[T]) Length() (size int) {
+	//KIM: the head is loaded first, since the tail never moves backwards
+	// it will always be greater than or equal to the head
+	head := atomic.LoadUint64(&q.head)
+	tail := atomic.LoadUint64(&q.tail)
+	if size = int(tail - head); size > len(q.data) {
+		size = len(q.data)
+	}
+	return
+}
+
+func (q *queueSPSC[T]) Capacity() (capacity int) {
+	return len(q.data)
+}
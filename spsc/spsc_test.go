@@ -0,0 +1,201 @@
+package spsc_test
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+	finite "github.com/antonio-alexander/go-queue/finite"
+	finite_tests "github.com/antonio-alexander/go-queue/finite/tests"
+	spsc "github.com/antonio-alexander/go-queue/spsc"
+	goqueue_tests "github.com/antonio-alexander/go-queue/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	mustTimeout = time.Second
+	mustRate    = time.Millisecond
+)
+
+func init() {
+	rand.Seed(int64(time.Now().Nanosecond()))
+}
+
+func TestSPSCQueue(t *testing.T) {
+	t.Run("Test Enqueue", finite_tests.TestEnqueue(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return spsc.New(size)
+	}))
+	t.Run("Test Enqueue Multiple", finite_tests.TestEnqueueMultiple(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+	} {
+		return spsc.New(size)
+	}))
+	t.Run("Test Enqueue Event", finite_tests.TestEnqueueEvent(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Event
+	} {
+		return spsc.New(size)
+	}))
+	t.Run("Test Capacity", finite_tests.TestCapacity(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		finite.Capacity
+	} {
+		return spsc.New(size)
+	}))
+	t.Run("Test Producer Consumer", func(t *testing.T) {
+		//KIM: the ring is much smaller than the number of items, so the
+		// producer and consumer will wrap around many times
+		const nItems, size = 100000, 16
+
+		q := spsc.NewOf[int](size)
+		defer q.Close()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < nItems; {
+				if overflow := q.Enqueue(i); overflow {
+					runtime.Gosched()
+					continue
+				}
+				i++
+			}
+		}()
+		for expected := 0; expected < nItems; {
+			items := q.DequeueMultiple(size / 2)
+			if len(items) <= 0 {
+				runtime.Gosched()
+			}
+			for _, item := range items {
+				if !assert.Equal(t, expected, item) {
+					return
+				}
+				expected++
+			}
+			assert.LessOrEqual(t, q.Length(), size)
+		}
+		<-done
+	})
+	t.Run("Test Close", func(t *testing.T) {
+		q := spsc.NewOf[int](4)
+		q.EnqueueMultiple([]int{1, 2, 3})
+		q.Dequeue()
+		assert.Equal(t, []int{2, 3}, q.Close())
+		assert.True(t, q.Enqueue(4))
+		_, overflow := q.EnqueueMultiple([]int{4})
+		assert.True(t, overflow)
+		assert.Nil(t, q.Close())
+	})
+}
+
+func TestQueue(t *testing.T) {
+	t.Run("Test Dequeue", goqueue_tests.TestDequeue(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return spsc.New(size)
+	}))
+	t.Run("Test Dequeue Event", goqueue_tests.TestDequeueEvent(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Event
+	} {
+		return spsc.New(size)
+	}))
+	t.Run("Test Dequeue Multiple", goqueue_tests.TestDequeueMultiple(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return spsc.New(size)
+	}))
+	t.Run("Test Flush", goqueue_tests.TestFlush(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return spsc.New(size)
+	}))
+	t.Run("Test Event", goqueue_tests.TestEvent(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Event
+	} {
+		return spsc.New(size)
+	}))
+	t.Run("Test Length", goqueue_tests.TestLength(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+		goqueue.Length
+	} {
+		return spsc.New(size)
+	}))
+	t.Run("Test Queue", goqueue_tests.TestQueue(t, mustRate, mustTimeout, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return spsc.New(size)
+	}))
+	t.Run("Test Asynchronous", goqueue_tests.TestAsync(t, func(size int) interface {
+		goqueue.Owner
+		goqueue.Enqueuer
+		goqueue.Dequeuer
+	} {
+		return spsc.New(size)
+	}))
+}
+
+// benchmarkProducerConsumer will measure the time it takes for one producer
+// to send b.N items to one consumer through the given queue
+func benchmarkProducerConsumer(b *testing.B, q interface {
+	goqueue.EnqueuerOf[int]
+	goqueue.DequeuerOf[int]
+}) {
+	var wg sync.WaitGroup
+
+	b.ResetTimer()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < b.N; {
+			if overflow := q.Enqueue(i); overflow {
+				runtime.Gosched()
+				continue
+			}
+			i++
+		}
+	}()
+	for n := 0; n < b.N; {
+		if _, underflow := q.Dequeue(); underflow {
+			runtime.Gosched()
+			continue
+		}
+		n++
+	}
+	wg.Wait()
+}
+
+func BenchmarkSPSC(b *testing.B) {
+	benchmarkProducerConsumer(b, spsc.NewOf[int](1024))
+}
+
+func BenchmarkFinite(b *testing.B) {
+	benchmarkProducerConsumer(b, finite.NewOf[int](1024))
+}
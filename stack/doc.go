@@ -0,0 +1,11 @@
+// Copyright 2022 antonio-alexander. All rights reserved.
+// Use of this source code is governed by an MIT
+// license that can be found in the LICENSE file.
+
+/*
+	Package stack provides bounded and unbounded LIFO stacks that implement
+	the same interfaces as the queues (e.g. Enqueuer and Dequeuer) so that
+	code written against those interfaces can use a stack without changes,
+	the newest item is always dequeued first
+*/
+package stack
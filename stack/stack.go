@@ -0,0 +1,88 @@
+package stack
+
+import (
+	goqueue "github.com/antonio-alexander/go-queue"
+	finite "github.com/antonio-alexander/go-queue/finite"
+	infinite "github.com/antonio-alexander/go-queue/infinite"
+)
+
+//deque describes the queue a stack is built on top of, items are pushed
+// onto and popped from the back of the queue
+type deque interface {
+	goqueue.Owner
+	goqueue.Enqueuer
+	goqueue.Dequeuer
+	goqueue.DequeueFromBacker
+	goqueue.Peeker
+	goqueue.PeekFromTailer
+	goqueue.Length
+	goqueue.Event
+}
+
+type stack struct {
+	deque
+}
+
+//NewFinite can be used to create a bounded stack with the given size, if
+// size is less than one, it will be one; if the stack is full, enqueue
+// will overflow
+func NewFinite(size int) interface {
+	goqueue.Owner
+	goqueue.Enqueuer
+	goqueue.Dequeuer
+	goqueue.Peeker
+	goqueue.Length
+	goqueue.Event
+} {
+	return &stack{deque: finite.New(size)}
+}
+
+//NewInfinite can be used to create an un-bounded stack that will grow by
+// growSize when full, if growSize is less than one, it will be one
+func NewInfinite(growSize int) interface {
+	goqueue.Owner
+	goqueue.Enqueuer
+	goqueue.Dequeuer
+	goqueue.Peeker
+	goqueue.Length
+	goqueue.Event
+} {
+	return &stack{deque: infinite.New(growSize)}
+}
+
+//reverse will reverse the order of the items (in place) so that they're
+// in the order they'd be dequeued
+func reverse(items []interface{}) []interface{} {
+	for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+		items[i], items[j] = items[j], items[i]
+	}
+	return items
+}
+
+func (s *stack) Close() (remainingElements []interface{}) {
+	return reverse(s.deque.Close())
+}
+
+func (s *stack) Dequeue() (item interface{}, underflow bool) {
+	return s.deque.DequeueBack()
+}
+
+func (s *stack) DequeueMultiple(n int) (items []interface{}) {
+	return s.deque.DequeueMultipleBack(n)
+}
+
+func (s *stack) Flush() (items []interface{}) {
+	return reverse(s.deque.Flush())
+}
+
+func (s *stack) Peek() (items []interface{}) {
+	return reverse(s.deque.Peek())
+}
+
+func (s *stack) PeekHead() (item interface{}, underflow bool) {
+	return s.deque.PeekTail()
+}
+
+func (s *stack) PeekFromHead(n int) (items []interface{}) {
+	return s.deque.PeekFromTail(n)
+}
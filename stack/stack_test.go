@@ -0,0 +1,137 @@
+package stack_test
+
+import (
+	"testing"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+	stack "github.com/antonio-alexander/go-queue/stack"
+	goqueue_tests "github.com/antonio-alexander/go-queue/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const casef = "case: %s"
+
+type newStack func(size int) interface {
+	goqueue.Owner
+	goqueue.Enqueuer
+	goqueue.Dequeuer
+	goqueue.Peeker
+	goqueue.Length
+	goqueue.Event
+}
+
+var stacks = map[string]newStack{
+	"finite":   stack.NewFinite,
+	"infinite": stack.NewInfinite,
+}
+
+func TestStack(t *testing.T) {
+	t.Run("Test LIFO", func(t *testing.T) {
+		for cDesc, newStack := range stacks {
+			//enqueue items and confirm that they're peeked and dequeued
+			// newest first
+			s := newStack(5)
+			_, overflow := s.EnqueueMultiple([]interface{}{1, 2, 3})
+			assert.False(t, overflow, casef, cDesc)
+			assert.False(t, s.Enqueue(4), casef, cDesc)
+			assert.Equal(t, 4, s.Length(), casef, cDesc)
+			item, underflow := s.PeekHead()
+			assert.False(t, underflow, casef, cDesc)
+			assert.Equal(t, 4, item, casef, cDesc)
+			assert.Equal(t, []interface{}{4, 3}, s.PeekFromHead(2), casef, cDesc)
+			assert.Equal(t, []interface{}{4, 3, 2, 1}, s.Peek(), casef, cDesc)
+			item, underflow = s.Dequeue()
+			assert.False(t, underflow, casef, cDesc)
+			assert.Equal(t, 4, item, casef, cDesc)
+			assert.Equal(t, []interface{}{3, 2}, s.DequeueMultiple(2), casef, cDesc)
+
+			//push more items and confirm that they're dequeued before
+			// the items that were already in the stack
+			assert.False(t, s.Enqueue(5), casef, cDesc)
+			assert.False(t, s.Enqueue(6), casef, cDesc)
+			assert.Equal(t, []interface{}{6, 5, 1}, s.Flush(), casef, cDesc)
+			_, underflow = s.Dequeue()
+			assert.True(t, underflow, casef, cDesc)
+			_, underflow = s.PeekHead()
+			assert.True(t, underflow, casef, cDesc)
+			assert.Empty(t, s.DequeueMultiple(1), casef, cDesc)
+
+			//confirm that close returns the remaining items newest first
+			_, overflow = s.EnqueueMultiple([]interface{}{7, 8, 9})
+			assert.False(t, overflow, casef, cDesc)
+			assert.Equal(t, []interface{}{9, 8, 7}, s.Close(), casef, cDesc)
+		}
+	})
+	t.Run("Test Bounded", func(t *testing.T) {
+		//fill the bounded stack and confirm that it overflows, but the
+		// unbounded stack doesn't
+		s := stack.NewFinite(2)
+		remaining, overflow := s.EnqueueMultiple([]interface{}{1, 2, 3})
+		assert.True(t, overflow)
+		assert.Equal(t, []interface{}{3}, remaining)
+		assert.True(t, s.Enqueue(3))
+		item, _ := s.Dequeue()
+		assert.Equal(t, 2, item)
+		assert.False(t, s.Enqueue(3))
+		assert.Equal(t, []interface{}{3, 1}, s.Close())
+		s = stack.NewInfinite(1)
+		for i := 0; i < 10; i++ {
+			assert.False(t, s.Enqueue(i))
+		}
+		item, _ = s.Dequeue()
+		assert.Equal(t, 9, item)
+		assert.Len(t, s.Close(), 9)
+	})
+	t.Run("Test Dequeuer", func(t *testing.T) {
+		//confirm that a consumer written against goqueue.Dequeuer can
+		// use a stack without changes
+		consume := func(queue goqueue.Dequeuer) (items []interface{}) {
+			for {
+				item, underflow := queue.Dequeue()
+				if underflow {
+					return
+				}
+				items = append(items, item)
+			}
+		}
+		for cDesc, newStack := range stacks {
+			s := newStack(3)
+			_, overflow := s.EnqueueMultiple([]interface{}{1, 2, 3})
+			assert.False(t, overflow, casef, cDesc)
+			assert.Equal(t, []interface{}{3, 2, 1}, consume(s), casef, cDesc)
+			s.Close()
+		}
+	})
+}
+
+func TestQueue(t *testing.T) {
+	//KIM: the test suites that don't depend on FIFO order can be used
+	// as-is
+	for cDesc, newStack := range stacks {
+		newStack := newStack
+		t.Run("Test Event "+cDesc, goqueue_tests.TestEvent(t, func(size int) interface {
+			goqueue.Owner
+			goqueue.Enqueuer
+			goqueue.Dequeuer
+			goqueue.Event
+		} {
+			return newStack(size)
+		}))
+		t.Run("Test Length "+cDesc, goqueue_tests.TestLength(t, func(size int) interface {
+			goqueue.Owner
+			goqueue.Enqueuer
+			goqueue.Dequeuer
+			goqueue.Length
+		} {
+			return newStack(size)
+		}))
+		t.Run("Test Asynchronous "+cDesc, goqueue_tests.TestAsync(t, func(size int) interface {
+			goqueue.Owner
+			goqueue.Enqueuer
+			goqueue.Dequeuer
+		} {
+			return newStack(size)
+		}))
+	}
+}
@@ -723,6 +723,835 @@ func TestAsync(t *testing.T, newQueue func(int) interface {
 	}
 }
 
+// TestQueueOf can be used to verify the type-safe version of a queue; it
+// mirrors TestQueue, but it uses the Example type directly rather than
+// converting from empty interface:
+//  1. Use the newQueue() function to create a queue of the size for the case
+//  2. Verify that Dequeue() and PeekHead() underflow and return the zero value
+//  3. Use EnqueueMultiple() to place all the examples in the queue
+//  4. Use Peek(), PeekHead() and PeekFromHead() to verify order without removing items
+//  5. Use Dequeue(), DequeueMultiple() and Flush() to remove the items in order
+//  6. Use the Close() function to clean up all internal pointers for the queue
+func TestQueueOf(t *testing.T, newQueue func(int) interface {
+	goqueue.OwnerOf[*goqueue.Example]
+	goqueue.EnqueuerOf[*goqueue.Example]
+	goqueue.DequeuerOf[*goqueue.Example]
+	goqueue.PeekerOf[*goqueue.Example]
+}) func(*testing.T) {
+	return func(t *testing.T) {
+		cases := map[string]struct {
+			iSize     int
+			iExamples []*goqueue.Example
+		}{
+			"single": {
+				iSize:     1,
+				iExamples: []*goqueue.Example{{Int: 1}},
+			},
+			"multiple": {
+				iSize:     5,
+				iExamples: []*goqueue.Example{{Int: 1}, {Int: 2}, {Int: 3}, {Int: 4}, {Int: 5}},
+			},
+			"random": {
+				iSize:     10,
+				iExamples: goqueue.ExampleGen(10),
+			},
+		}
+		for cDesc, c := range cases {
+			q := newQueue(c.iSize)
+			example, underflow := q.Dequeue()
+			assert.True(t, underflow, casef, cDesc)
+			assert.Nil(t, example, casef, cDesc)
+			example, underflow = q.PeekHead()
+			assert.True(t, underflow, casef, cDesc)
+			assert.Nil(t, example, casef, cDesc)
+			remaining, overflow := q.EnqueueMultiple(c.iExamples)
+			assert.False(t, overflow, casef, cDesc)
+			assert.Empty(t, remaining, casef, cDesc)
+			assert.Equal(t, c.iExamples, q.Peek(), casef, cDesc)
+			assert.Equal(t, c.iExamples[:1], q.PeekFromHead(1), casef, cDesc)
+			example, underflow = q.PeekHead()
+			assert.False(t, underflow, casef, cDesc)
+			assert.Equal(t, c.iExamples[0], example, casef, cDesc)
+			example, underflow = q.Dequeue()
+			assert.False(t, underflow, casef, cDesc)
+			assert.Equal(t, c.iExamples[0], example, casef, cDesc)
+			n := (len(c.iExamples) - 1) / 2
+			examples := q.DequeueMultiple(n)
+			assert.Equal(t, n, len(examples), casef, cDesc)
+			for i, example := range examples {
+				assert.Equal(t, c.iExamples[1+i], example, casef, cDesc)
+			}
+			examples = q.Flush()
+			assert.Equal(t, len(c.iExamples)-1-n, len(examples), casef, cDesc)
+			for i, example := range examples {
+				assert.Equal(t, c.iExamples[1+n+i], example, casef, cDesc)
+			}
+			assert.Empty(t, q.Close(), casef, cDesc)
+		}
+	}
+}
+
+// TestDequeueCtx can be used to verify the context-aware dequeue functions, it
+// will confirm that:
+//   - DequeueCtx() returns ctx.Err() when the context times out or is cancelled
+//   - DequeueCtx() wakes up and returns an item when an item is enqueued
+//   - DequeueMultipleCtx() blocks until n items have been dequeued
+//   - DequeueCtx() returns ErrQueueClosed if the queue is closed while waiting
+//
+// Some assumptions this test does make:
+//   - your queue maintains order
+//   - your queue has room for at least one item
+func TestDequeueCtx(t *testing.T, timeout time.Duration, newQueue func(size int) interface {
+	goqueue.Owner
+	goqueue.Enqueuer
+	goqueue.DequeuerCtx
+}) func(*testing.T) {
+	return func(t *testing.T) {
+		const wait = 10 * time.Millisecond
+
+		//generate examples
+		examples := goqueue.ExampleGenFloat64(5)
+
+		//create the queue
+		q := newQueue(len(examples))
+		defer q.Close()
+
+		//attempt to dequeue (confirm timeout)
+		ctx, cancel := context.WithTimeout(context.TODO(), wait)
+		defer cancel()
+		item, err := q.DequeueCtx(ctx)
+		cancel()
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Nil(t, item)
+
+		//attempt to dequeue (confirm cancel)
+		ctx, cancel = context.WithCancel(context.TODO())
+		cancel()
+		_, err = q.DequeueCtx(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		//enqueue an item while waiting and confirm that dequeue wakes up
+		go func() {
+			<-time.After(wait)
+			overflow := q.Enqueue(examples[0])
+			assert.False(t, overflow)
+		}()
+		ctx, cancel = context.WithTimeout(context.TODO(), timeout)
+		defer cancel()
+		item, err = q.DequeueCtx(ctx)
+		cancel()
+		assert.Nil(t, err)
+		assert.Equal(t, examples[0], goqueue.ExampleConvertSingle(item))
+
+		//enqueue items one at a time and confirm that dequeue multiple
+		// waits until all of them have been dequeued
+		go func() {
+			for _, example := range examples {
+				<-time.After(time.Millisecond)
+				overflow := q.Enqueue(example)
+				assert.False(t, overflow)
+			}
+		}()
+		ctx, cancel = context.WithTimeout(context.TODO(), timeout)
+		defer cancel()
+		items, err := q.DequeueMultipleCtx(ctx, len(examples))
+		cancel()
+		assert.Nil(t, err)
+		assert.Equal(t, examples, goqueue.ExampleConvertMultiple(items))
+
+		//confirm that dequeue multiple returns the items dequeued
+		// before the context was done
+		overflow := q.Enqueue(examples[0])
+		assert.False(t, overflow)
+		ctx, cancel = context.WithTimeout(context.TODO(), wait)
+		defer cancel()
+		items, err = q.DequeueMultipleCtx(ctx, len(examples))
+		cancel()
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, examples[:1], goqueue.ExampleConvertMultiple(items))
+
+		//close the queue while waiting and confirm that dequeue
+		// returns the closed error
+		go func() {
+			<-time.After(wait)
+			q.Close()
+		}()
+		ctx, cancel = context.WithTimeout(context.TODO(), timeout)
+		defer cancel()
+		_, err = q.DequeueCtx(ctx)
+		cancel()
+		assert.ErrorIs(t, err, goqueue.ErrQueueClosed)
+	}
+}
+
+// TestPeekCtx can be used to verify the context-aware peek functions, it will
+// confirm that PeekHeadCtx() and PeekFromHeadCtx() wait until items are available
+// without removing them and that ctx.Err() or ErrQueueClosed are returned
+func TestPeekCtx(t *testing.T, timeout time.Duration, newQueue func(size int) interface {
+	goqueue.Owner
+	goqueue.Enqueuer
+	goqueue.Length
+	goqueue.PeekerCtx
+}) func(*testing.T) {
+	return func(t *testing.T) {
+		const wait = 10 * time.Millisecond
+
+		//generate examples
+		examples := goqueue.ExampleGenFloat64(5)
+
+		//create the queue
+		q := newQueue(len(examples))
+		defer q.Close()
+
+		//attempt to peek (confirm timeout)
+		ctx, cancel := context.WithTimeout(context.TODO(), wait)
+		defer cancel()
+		_, err := q.PeekHeadCtx(ctx)
+		cancel()
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		//enqueue an item while waiting and confirm that peek wakes up
+		// without removing the item
+		go func() {
+			<-time.After(wait)
+			overflow := q.Enqueue(examples[0])
+			assert.False(t, overflow)
+		}()
+		ctx, cancel = context.WithTimeout(context.TODO(), timeout)
+		defer cancel()
+		item, err := q.PeekHeadCtx(ctx)
+		cancel()
+		assert.Nil(t, err)
+		assert.Equal(t, examples[0], goqueue.ExampleConvertSingle(item))
+		assert.Equal(t, 1, q.Length())
+
+		//enqueue the remaining items while waiting and confirm that
+		// peek from head waits for all of them
+		go func() {
+			for _, example := range examples[1:] {
+				<-time.After(time.Millisecond)
+				overflow := q.Enqueue(example)
+				assert.False(t, overflow)
+			}
+		}()
+		ctx, cancel = context.WithTimeout(context.TODO(), timeout)
+		defer cancel()
+		items, err := q.PeekFromHeadCtx(ctx, len(examples))
+		cancel()
+		assert.Nil(t, err)
+		assert.Equal(t, examples, goqueue.ExampleConvertMultiple(items))
+		assert.Equal(t, len(examples), q.Length())
+
+		//close the queue and confirm that peek returns the closed error
+		q.Close()
+		ctx, cancel = context.WithTimeout(context.TODO(), timeout)
+		defer cancel()
+		_, err = q.PeekHeadCtx(ctx)
+		cancel()
+		assert.ErrorIs(t, err, goqueue.ErrQueueClosed)
+	}
+}
+
+// TestStats can be used to verify that the counters returned by Stats() reflect the
+// operations performed on the queue, it will confirm that:
+//   - items enqueued and dequeued are counted (no matter how they're dequeued)
+//   - dequeue operations on an empty queue are counted as underflow
+//   - the high-water mark is the most items that have been in the queue at once
+//   - garbage collections are counted (if the queue is a GarbageCollecter)
+//
+// Some assumptions this test does make:
+//   - your queue has room for the given number of items
+func TestStats(t *testing.T, newQueue func(size int) interface {
+	goqueue.Owner
+	goqueue.Enqueuer
+	goqueue.Dequeuer
+	goqueue.Statser
+}) func(*testing.T) {
+	return func(t *testing.T) {
+		//generate examples
+		examples := goqueue.ExampleGenFloat64(10)
+		items := make([]interface{}, 0, len(examples))
+		for _, example := range examples {
+			items = append(items, example)
+		}
+
+		//create the queue and confirm that nothing has been counted
+		q := newQueue(len(examples))
+		defer q.Close()
+		assert.Equal(t, goqueue.Stats{}, q.Stats())
+
+		//enqueue the examples, dequeue them (one of each) and confirm
+		// that they've been counted
+		overflow := q.Enqueue(items[0])
+		assert.False(t, overflow)
+		remaining, overflow := q.EnqueueMultiple(items[1:])
+		assert.False(t, overflow)
+		assert.Empty(t, remaining)
+		_, underflow := q.Dequeue()
+		assert.False(t, underflow)
+		assert.Len(t, q.DequeueMultiple(2), 2)
+		assert.Len(t, q.Flush(), len(examples)-3)
+		stats := q.Stats()
+		assert.Equal(t, uint64(len(examples)), stats.Enqueued)
+		assert.Equal(t, uint64(len(examples)), stats.Dequeued)
+		assert.Equal(t, uint64(len(examples)), stats.HighWaterMark)
+		assert.Zero(t, stats.Underflow)
+		assert.Zero(t, stats.Overflow)
+
+		//attempt to dequeue from the empty queue and confirm that
+		// underflow has been counted
+		_, underflow = q.Dequeue()
+		assert.True(t, underflow)
+		assert.Empty(t, q.DequeueMultiple(1))
+		assert.Empty(t, q.Flush())
+		assert.Equal(t, uint64(3), q.Stats().Underflow)
+
+		//enqueue a single item and confirm the high-water mark doesn't
+		// change
+		overflow = q.Enqueue(items[0])
+		assert.False(t, overflow)
+		stats = q.Stats()
+		assert.Equal(t, uint64(len(examples)+1), stats.Enqueued)
+		assert.Equal(t, uint64(len(examples)), stats.HighWaterMark)
+
+		//garbage collect and confirm that it was counted
+		if gc, ok := q.(goqueue.GarbageCollecter); ok {
+			gc.GarbageCollect()
+			assert.Equal(t, uint64(1), q.Stats().GarbageCollections)
+		}
+	}
+}
+
 //REVIEW: implement tests for sanity/security checks
 // * When using dequeue methods that output slices, can we ensure we don't accidentally leak the
 //   underlying slice?
+
+// TestDequeueBack can be used to verify that DequeueBack() and DequeueMultipleBack() remove
+// items from the back of the queue (the opposite end of Dequeue()) and return them last item
+// first. Items are dequeued from the front before more items are enqueued so that the queue
+// wraps around (or crosses the boundaries of) its underlying data structure
+func TestDequeueBack(t *testing.T, newQueue func(size int) interface {
+	goqueue.Owner
+	goqueue.Enqueuer
+	goqueue.Dequeuer
+	goqueue.DequeueFromBacker
+}) func(*testing.T) {
+	return func(t *testing.T) {
+		cases := map[string]struct {
+			iSize       int
+			iEnqueue    []interface{}
+			iDequeue    int
+			iEnqueueTwo []interface{}
+			iN          int
+			oItem       interface{}
+			oUnderflow  bool
+			oItems      []interface{}
+			oRemaining  []interface{}
+		}{
+			"empty": {
+				iSize:      5,
+				iN:         1,
+				oUnderflow: true,
+			},
+			"single": {
+				iSize:    5,
+				iEnqueue: []interface{}{1},
+				iN:       1,
+				oItem:    1,
+			},
+			"multiple": {
+				iSize:      5,
+				iEnqueue:   []interface{}{1, 2, 3, 4, 5},
+				iN:         2,
+				oItem:      5,
+				oItems:     []interface{}{4, 3},
+				oRemaining: []interface{}{1, 2},
+			},
+			"greater than size": {
+				iSize:    5,
+				iEnqueue: []interface{}{1, 2, 3},
+				iN:       5,
+				oItem:    3,
+				oItems:   []interface{}{2, 1},
+			},
+			"wrapped": {
+				iSize:       5,
+				iEnqueue:    []interface{}{1, 2, 3, 4, 5},
+				iDequeue:    2,
+				iEnqueueTwo: []interface{}{6, 7},
+				iN:          3,
+				oItem:       7,
+				oItems:      []interface{}{6, 5, 4},
+				oRemaining:  []interface{}{3},
+			},
+		}
+		for cDesc, c := range cases {
+			q := newQueue(c.iSize)
+			_, overflow := q.EnqueueMultiple(c.iEnqueue)
+			assert.False(t, overflow, casef, cDesc)
+			assert.Len(t, q.DequeueMultiple(c.iDequeue), c.iDequeue, casef, cDesc)
+			_, overflow = q.EnqueueMultiple(c.iEnqueueTwo)
+			assert.False(t, overflow, casef, cDesc)
+			item, underflow := q.DequeueBack()
+			assert.Equal(t, c.oUnderflow, underflow, casef, cDesc)
+			if !underflow {
+				assert.Equal(t, c.oItem, item, casef, cDesc)
+			}
+			items := q.DequeueMultipleBack(c.iN)
+			assert.Equal(t, len(c.oItems), len(items), casef, cDesc)
+			for i := range c.oItems {
+				assert.Equal(t, c.oItems[i], items[i], casef, cDesc)
+			}
+			remaining := q.Close()
+			assert.Equal(t, len(c.oRemaining), len(remaining), casef, cDesc)
+			for i := range c.oRemaining {
+				assert.Equal(t, c.oRemaining[i], remaining[i], casef, cDesc)
+			}
+		}
+	}
+}
+
+// TestPeekFromTail can be used to verify that PeekTail() and PeekFromTail() return the item(s)
+// at the back of the queue (last item first) without removing them
+func TestPeekFromTail(t *testing.T, newQueue func(size int) interface {
+	goqueue.Owner
+	goqueue.Enqueuer
+	goqueue.Dequeuer
+	goqueue.PeekFromTailer
+}) func(*testing.T) {
+	return func(t *testing.T) {
+		cases := map[string]struct {
+			iSize         int
+			iExamples     []*goqueue.Example
+			iDequeue      int
+			iPeekFromTail int
+			oUnderflow    bool
+			oTail         *goqueue.Example
+			oPeeked       []*goqueue.Example
+		}{
+			"empty queue": {
+				iSize:         5,
+				iPeekFromTail: 1,
+				oUnderflow:    true,
+			},
+			"min": {
+				iSize:         5,
+				iExamples:     []*goqueue.Example{{Int: 1}, {Int: 2}, {Int: 3}, {Int: 4}, {Int: 5}},
+				iPeekFromTail: 1,
+				oTail:         &goqueue.Example{Int: 5},
+				oPeeked:       []*goqueue.Example{{Int: 5}},
+			},
+			"zero": {
+				iSize:         5,
+				iExamples:     []*goqueue.Example{{Int: 1}, {Int: 2}, {Int: 3}, {Int: 4}, {Int: 5}},
+				iPeekFromTail: 0,
+				oTail:         &goqueue.Example{Int: 5},
+			},
+			"max": {
+				iSize:         5,
+				iExamples:     []*goqueue.Example{{Int: 1}, {Int: 2}, {Int: 3}, {Int: 4}, {Int: 5}},
+				iPeekFromTail: 5,
+				oTail:         &goqueue.Example{Int: 5},
+				oPeeked:       []*goqueue.Example{{Int: 5}, {Int: 4}, {Int: 3}, {Int: 2}, {Int: 1}},
+			},
+			"max+1": {
+				iSize:         5,
+				iExamples:     []*goqueue.Example{{Int: 1}, {Int: 2}, {Int: 3}, {Int: 4}, {Int: 5}},
+				iPeekFromTail: 6,
+				oTail:         &goqueue.Example{Int: 5},
+				oPeeked:       []*goqueue.Example{{Int: 5}, {Int: 4}, {Int: 3}, {Int: 2}, {Int: 1}},
+			},
+			"dequeued": {
+				iSize:         5,
+				iExamples:     []*goqueue.Example{{Int: 1}, {Int: 2}, {Int: 3}, {Int: 4}, {Int: 5}},
+				iDequeue:      3,
+				iPeekFromTail: 3,
+				oTail:         &goqueue.Example{Int: 5},
+				oPeeked:       []*goqueue.Example{{Int: 5}, {Int: 4}},
+			},
+		}
+		for cDesc, c := range cases {
+			q := newQueue(c.iSize)
+			for _, input := range c.iExamples {
+				overflow := q.Enqueue(input)
+				assert.False(t, overflow, casef, cDesc)
+			}
+			assert.Len(t, q.DequeueMultiple(c.iDequeue), c.iDequeue, casef, cDesc)
+			item, underflow := q.PeekTail()
+			assert.Equal(t, c.oUnderflow, underflow, casef, cDesc)
+			if !underflow {
+				assert.Equal(t, c.oTail, goqueue.ExampleConvertSingle(item), casef, cDesc)
+			}
+			peeked := goqueue.ExampleConvertMultiple(q.PeekFromTail(c.iPeekFromTail))
+			if assert.Equal(t, len(c.oPeeked), len(peeked), casef, cDesc) {
+				for i, example := range peeked {
+					assert.Equal(t, c.oPeeked[i], example, casef, cDesc)
+				}
+			}
+
+			//confirm that peeking didn't remove any items
+			assert.Len(t, q.Close(), len(c.iExamples)-c.iDequeue, casef, cDesc)
+		}
+	}
+}
+
+// TestRemover can be used to verify that RemoveIf() removes (and returns) every item the
+// predicate is true for while the remaining items keep their order and that Find() and
+// Contains() search from the front of the queue. Items are dequeued from the front before
+// more items are enqueued so that the queue wraps around (or crosses the boundaries of) its
+// underlying data structure. The queue is also used concurrently to verify that every item is
+// either dequeued or removed exactly once
+func TestRemover(t *testing.T, newQueue func(size int) interface {
+	goqueue.Owner
+	goqueue.Enqueuer
+	goqueue.Dequeuer
+	goqueue.Remover
+	goqueue.Statser
+}) func(*testing.T) {
+	return func(t *testing.T) {
+		even := func(item interface{}) bool { return item.(int)%2 == 0 }
+		cases := map[string]struct {
+			iSize       int
+			iEnqueue    []interface{}
+			iDequeue    int
+			iEnqueueTwo []interface{}
+			oIndex      int
+			oItem       interface{}
+			oOk         bool
+			oRemoved    []interface{}
+			oRemaining  []interface{}
+		}{
+			"empty": {
+				iSize:  5,
+				oIndex: -1,
+			},
+			"none": {
+				iSize:      5,
+				iEnqueue:   []interface{}{1, 3, 5},
+				oIndex:     -1,
+				oRemaining: []interface{}{1, 3, 5},
+			},
+			"all": {
+				iSize:    5,
+				iEnqueue: []interface{}{2, 4, 6},
+				oIndex:   0,
+				oItem:    2,
+				oOk:      true,
+				oRemoved: []interface{}{2, 4, 6},
+			},
+			"some": {
+				iSize:      5,
+				iEnqueue:   []interface{}{1, 2, 3, 4, 5},
+				oIndex:     1,
+				oItem:      2,
+				oOk:        true,
+				oRemoved:   []interface{}{2, 4},
+				oRemaining: []interface{}{1, 3, 5},
+			},
+			"wrapped": {
+				iSize:       5,
+				iEnqueue:    []interface{}{1, 2, 3, 4, 5},
+				iDequeue:    3,
+				iEnqueueTwo: []interface{}{6, 7, 8},
+				oIndex:      0,
+				oItem:       4,
+				oOk:         true,
+				oRemoved:    []interface{}{4, 6, 8},
+				oRemaining:  []interface{}{5, 7},
+			},
+		}
+		for cDesc, c := range cases {
+			q := newQueue(c.iSize)
+			_, overflow := q.EnqueueMultiple(c.iEnqueue)
+			assert.False(t, overflow, casef, cDesc)
+			assert.Len(t, q.DequeueMultiple(c.iDequeue), c.iDequeue, casef, cDesc)
+			_, overflow = q.EnqueueMultiple(c.iEnqueueTwo)
+			assert.False(t, overflow, casef, cDesc)
+			index, item, ok := q.Find(even)
+			assert.Equal(t, c.oIndex, index, casef, cDesc)
+			assert.Equal(t, c.oOk, ok, casef, cDesc)
+			if ok {
+				assert.Equal(t, c.oItem, item, casef, cDesc)
+			}
+			assert.Equal(t, c.oOk, q.Contains(even), casef, cDesc)
+			assert.Equal(t, c.oRemoved, q.RemoveIf(even), casef, cDesc)
+			assert.False(t, q.Contains(even), casef, cDesc)
+			assert.Empty(t, q.RemoveIf(even), casef, cDesc)
+
+			//confirm that the items removed are counted as dequeued
+			stats := q.Stats()
+			assert.Equal(t, len(c.oRemaining), int(stats.Enqueued-stats.Dequeued), casef, cDesc)
+
+			//confirm that the queue can still be used after items are
+			// removed
+			assert.False(t, q.Enqueue(10), casef, cDesc)
+			assert.Equal(t, append(c.oRemaining, 10), q.Close(), casef, cDesc)
+		}
+
+		//remove items while they're enqueued and dequeued concurrently and
+		// confirm that every item is either dequeued or removed once
+		const nItems = 1000
+
+		var wg sync.WaitGroup
+
+		q := newQueue(nItems)
+		defer q.Close()
+		stopper := make(chan struct{})
+		chDequeued, chRemoved := make(chan []interface{}, 1), make(chan []interface{}, 1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < nItems; i++ {
+				for q.Enqueue(i) {
+					runtime.Gosched()
+				}
+			}
+		}()
+		go func() {
+			var items []interface{}
+
+			defer func() { chDequeued <- items }()
+			for {
+				if item, underflow := q.Dequeue(); !underflow {
+					items = append(items, item)
+					continue
+				}
+				select {
+				case <-stopper:
+					items = append(items, q.Flush()...)
+					return
+				default:
+					runtime.Gosched()
+				}
+			}
+		}()
+		go func() {
+			var items []interface{}
+
+			defer func() { chRemoved <- items }()
+			for {
+				items = append(items, q.RemoveIf(func(item interface{}) bool {
+					return item.(int)%3 == 0
+				})...)
+				select {
+				case <-stopper:
+					return
+				default:
+					runtime.Gosched()
+				}
+			}
+		}()
+		wg.Wait()
+		close(stopper)
+		dequeued, removed := <-chDequeued, <-chRemoved
+		seen := make(map[int]int)
+		for _, item := range append(dequeued, removed...) {
+			seen[item.(int)]++
+		}
+		assert.Len(t, seen, nItems)
+		for item, n := range seen {
+			assert.Equal(t, 1, n, "item: %d", item)
+		}
+		for _, item := range removed {
+			assert.Zero(t, item.(int)%3)
+		}
+	}
+}
+
+// TestConditionalDequeue can be used to verify that DequeueIf() only removes the item at the
+// front of the queue if the predicate is true for it and that DequeueWhile() removes up to max
+// items from the front until the predicate is false
+func TestConditionalDequeue(t *testing.T, newQueue func(size int) interface {
+	goqueue.Owner
+	goqueue.Enqueuer
+	goqueue.Dequeuer
+	goqueue.ConditionalDequeuer
+}) func(*testing.T) {
+	return func(t *testing.T) {
+		less := func(n int) func(item interface{}) bool {
+			return func(item interface{}) bool { return item.(int) < n }
+		}
+		cases := map[string]struct {
+			iEnqueue   []interface{}
+			iPred      func(item interface{}) bool
+			iMax       int
+			oItem      interface{}
+			oUnderflow bool
+			oItems     []interface{}
+			oRemaining []interface{}
+		}{
+			"empty": {
+				iPred:      less(10),
+				iMax:       5,
+				oUnderflow: true,
+			},
+			"false": {
+				iEnqueue:   []interface{}{5, 1, 2},
+				iPred:      less(5),
+				iMax:       5,
+				oUnderflow: true,
+				oRemaining: []interface{}{5, 1, 2},
+			},
+			"until false": {
+				iEnqueue:   []interface{}{1, 2, 3, 9, 4},
+				iPred:      less(5),
+				iMax:       5,
+				oItem:      1,
+				oItems:     []interface{}{2, 3},
+				oRemaining: []interface{}{9, 4},
+			},
+			"max": {
+				iEnqueue:   []interface{}{1, 2, 3, 4, 5},
+				iPred:      less(10),
+				iMax:       2,
+				oItem:      1,
+				oItems:     []interface{}{2, 3},
+				oRemaining: []interface{}{4, 5},
+			},
+			"zero max": {
+				iEnqueue:   []interface{}{1, 2},
+				iPred:      less(10),
+				oItem:      1,
+				oRemaining: []interface{}{2},
+			},
+			"all": {
+				iEnqueue: []interface{}{1, 2, 3},
+				iPred:    less(10),
+				iMax:     5,
+				oItem:    1,
+				oItems:   []interface{}{2, 3},
+			},
+		}
+		for cDesc, c := range cases {
+			q := newQueue(10)
+			_, overflow := q.EnqueueMultiple(c.iEnqueue)
+			assert.False(t, overflow, casef, cDesc)
+			item, underflow := q.DequeueIf(c.iPred)
+			assert.Equal(t, c.oUnderflow, underflow, casef, cDesc)
+			if !underflow {
+				assert.Equal(t, c.oItem, item, casef, cDesc)
+			}
+			assert.Empty(t, q.DequeueWhile(c.iPred, 0), casef, cDesc)
+			items := q.DequeueWhile(c.iPred, c.iMax)
+			assert.Equal(t, len(c.oItems), len(items), casef, cDesc)
+			for i := range c.oItems {
+				assert.Equal(t, c.oItems[i], items[i], casef, cDesc)
+			}
+			assert.Equal(t, c.oRemaining, q.Close(), casef, cDesc)
+		}
+	}
+}
+
+// TestConditionalDequeueAsync can be used to verify that DequeueIf() and DequeueWhile() check
+// and remove items in the same critical section. Multiple consumers peek at the item at the front
+// of the queue and only dequeue it if it's still the item they peeked (or dequeue consecutive
+// items in batches) while the queue is being filled; it confirms that every item is dequeued
+// exactly once, that every item dequeued is the item that was peeked and that each batch is made
+// up of consecutive items
+func TestConditionalDequeueAsync(t *testing.T, newQueue func(size int) interface {
+	goqueue.Owner
+	goqueue.Enqueuer
+	goqueue.Dequeuer
+	goqueue.Peeker
+	goqueue.ConditionalDequeuer
+}) func(*testing.T) {
+	return func(t *testing.T) {
+		const nItems, nConsumers, max = 500, 4, 3
+
+		var wg sync.WaitGroup
+
+		q := newQueue(nItems)
+		defer q.Close()
+		stopper := make(chan struct{})
+		received := make(chan []int, 2*nConsumers)
+		for i := 0; i < nConsumers; i++ {
+			//peek, then only dequeue the item that was peeked
+			go func() {
+				var items []int
+
+				defer func() { received <- items }()
+				for {
+					peeked, underflow := q.PeekHead()
+					if !underflow {
+						item, underflow := q.DequeueIf(func(item interface{}) bool {
+							return item == peeked
+						})
+						if !underflow {
+							assert.Equal(t, peeked, item)
+							items = append(items, item.(int))
+						}
+						continue
+					}
+					select {
+					case <-stopper:
+						return
+					default:
+						runtime.Gosched()
+					}
+				}
+			}()
+
+			//dequeue batches of items while they're consecutive to the
+			// item at the front of the queue
+			go func() {
+				var items []int
+
+				defer func() { received <- items }()
+				for {
+					peeked, underflow := q.PeekHead()
+					if !underflow {
+						next := peeked.(int)
+						batch := q.DequeueWhile(func(item interface{}) bool {
+							if item.(int) != next {
+								return false
+							}
+							next++
+							return true
+						}, max)
+						assert.LessOrEqual(t, len(batch), max)
+						for i := 1; i < len(batch); i++ {
+							assert.Equal(t, batch[i-1].(int)+1, batch[i])
+						}
+						for _, item := range batch {
+							items = append(items, item.(int))
+						}
+						continue
+					}
+					select {
+					case <-stopper:
+						return
+					default:
+						runtime.Gosched()
+					}
+				}
+			}()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < nItems; i++ {
+				for q.Enqueue(i) {
+					runtime.Gosched()
+				}
+			}
+		}()
+		wg.Wait()
+		assert.Eventually(t, func() bool {
+			_, underflow := q.PeekHead()
+			return underflow
+		}, 10*time.Second, time.Millisecond)
+		close(stopper)
+		seen := make(map[int]int)
+		for i := 0; i < 2*nConsumers; i++ {
+			for _, item := range <-received {
+				seen[item]++
+			}
+		}
+		assert.Len(t, seen, nItems)
+		for item, n := range seen {
+			assert.Equal(t, 1, n, "item: %d", item)
+		}
+	}
+}
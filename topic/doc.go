@@ -0,0 +1,11 @@
+// Copyright 2022 antonio-alexander. All rights reserved.
+// Use of this source code is governed by an MIT
+// license that can be found in the LICENSE file.
+
+/*
+	Package topic provides fan-out publish/subscribe on top of queues: each
+	subscriber has its own queue (e.g. finite or infinite) and every item
+	that's published is enqueued into each subscriber's queue, so a slow
+	subscriber can't block the others
+*/
+package topic
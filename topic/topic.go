@@ -0,0 +1,103 @@
+package topic
+
+import (
+	"sync"
+)
+
+type subscription struct {
+	Storage
+	topic *topic
+}
+
+//KIM: the subscriptions are copy-on-write (they're never modified in place)
+// such that Publish() can take a copy and enqueue without holding the lock
+type topic struct {
+	sync.RWMutex
+	closed        bool
+	subscriptions []*subscription
+}
+
+//New can be used to create a topic, items that are published are only
+// received by subscriptions that exist when they're published
+func New() interface {
+	Publisher
+	Subscriber
+	Close()
+} {
+	return &topic{}
+}
+
+//remove will remove the subscription from the topic, ok will be false if
+// the subscription has already been removed
+func (t *topic) remove(s *subscription) (ok bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	for i := range t.subscriptions {
+		if t.subscriptions[i] == s {
+			subscriptions := make([]*subscription, 0, len(t.subscriptions)-1)
+			subscriptions = append(subscriptions, t.subscriptions[:i]...)
+			t.subscriptions = append(subscriptions, t.subscriptions[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+//Close will unsubscribe and close the queue of every subscription, any
+// items that haven't been dequeued are discarded; subscriptions created
+// after the topic is closed are closed immediately
+func (t *topic) Close() {
+	t.Lock()
+	defer t.Unlock()
+
+	for _, s := range t.subscriptions {
+		s.Storage.Close()
+	}
+	t.subscriptions, t.closed = nil, true
+}
+
+func (t *topic) Publish(item interface{}) (overflows int) {
+	t.RLock()
+	subscriptions := t.subscriptions
+	t.RUnlock()
+
+	//KIM: if a subscriber's queue blocks when it's full, publish will
+	// block too, but since the lock isn't held, subscribers can still
+	// subscribe and unsubscribe (which will unblock it)
+	for _, s := range subscriptions {
+		if overflow := s.Enqueue(item); overflow {
+			overflows++
+		}
+	}
+	return
+}
+
+func (t *topic) Subscribe(storage Storage) Subscription {
+	t.Lock()
+	defer t.Unlock()
+
+	s := &subscription{
+		Storage: storage,
+		topic:   t,
+	}
+	if t.closed {
+		storage.Close()
+		return s
+	}
+	t.subscriptions = append(t.subscriptions[:len(t.subscriptions):len(t.subscriptions)], s)
+	return s
+}
+
+func (t *topic) Subscribers() (n int) {
+	t.RLock()
+	defer t.RUnlock()
+	return len(t.subscriptions)
+}
+
+func (s *subscription) Unsubscribe() (remainingElements []interface{}) {
+	if !s.topic.remove(s) {
+		return nil
+	}
+	return s.Storage.Close()
+}
@@ -0,0 +1,196 @@
+package topic_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	finite "github.com/antonio-alexander/go-queue/finite"
+	infinite "github.com/antonio-alexander/go-queue/infinite"
+	topic "github.com/antonio-alexander/go-queue/topic"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	mustTimeout = time.Second
+	mustRate    = time.Millisecond
+	casef       = "case: %s"
+)
+
+func TestTopic(t *testing.T) {
+	t.Run("Test Fan Out", func(t *testing.T) {
+		const nItems = 10
+
+		//subscribe with both finite and infinite queues, publish items
+		// and confirm that every subscriber receives every item in order
+		tp := topic.New()
+		defer tp.Close()
+		subscriptions := []topic.Subscription{
+			tp.Subscribe(finite.New(nItems)),
+			tp.Subscribe(finite.New(nItems)),
+			tp.Subscribe(infinite.New(1)),
+		}
+		assert.Equal(t, len(subscriptions), tp.Subscribers())
+		for i := 0; i < nItems; i++ {
+			assert.Zero(t, tp.Publish(i))
+		}
+		for _, s := range subscriptions {
+			item, underflow := s.PeekHead()
+			assert.False(t, underflow)
+			assert.Equal(t, 0, item)
+			assert.Len(t, s.Peek(), nItems)
+			for i := 0; i < nItems; i++ {
+				item, underflow := s.Dequeue()
+				assert.False(t, underflow)
+				assert.Equal(t, i, item)
+			}
+			_, underflow = s.Dequeue()
+			assert.True(t, underflow)
+		}
+	})
+	t.Run("Test Slow Subscriber", func(t *testing.T) {
+		cases := map[string]struct {
+			iPolicy    finite.OverflowPolicy
+			oOverflows []int
+			oItems     []interface{}
+		}{
+			"reject": {
+				iPolicy:    finite.OverflowReject,
+				oOverflows: []int{0, 0, 1, 1},
+				oItems:     []interface{}{0, 1},
+			},
+			"drop oldest": {
+				iPolicy:    finite.OverflowDropOldest,
+				oOverflows: []int{0, 0, 0, 0},
+				oItems:     []interface{}{2, 3},
+			},
+			"drop newest": {
+				iPolicy:    finite.OverflowDropNewest,
+				oOverflows: []int{0, 0, 0, 0},
+				oItems:     []interface{}{0, 1},
+			},
+		}
+		for cDesc, c := range cases {
+			//subscribe with a small queue that's never dequeued from and
+			// confirm that its overflow policy is applied while the other
+			// subscriber receives every item
+			tp := topic.New()
+			slow := tp.Subscribe(finite.New(2, finite.WithOverflowPolicy(c.iPolicy)))
+			fast := tp.Subscribe(infinite.New(1))
+			for i, overflows := range c.oOverflows {
+				assert.Equal(t, overflows, tp.Publish(i), casef, cDesc)
+			}
+			assert.Equal(t, []interface{}{0, 1, 2, 3}, fast.Flush(), casef, cDesc)
+			assert.Equal(t, c.oItems, slow.Flush(), casef, cDesc)
+			tp.Close()
+		}
+	})
+	t.Run("Test Blocked Publish", func(t *testing.T) {
+		//subscribe with a full queue that blocks, publish and confirm that
+		// while publish is blocked, subscribers can subscribe and that
+		// unsubscribing the blocked subscriber unblocks publish; the
+		// subscriber that doesn't block is first so once it has received
+		// the item, publish is blocked (or about to be)
+		tp := topic.New()
+		defer tp.Close()
+		fast := tp.Subscribe(infinite.New(1))
+		defer fast.Unsubscribe()
+		blocked := tp.Subscribe(finite.New(1, finite.WithOverflowPolicy(finite.OverflowBlock)))
+		assert.Zero(t, tp.Publish(0))
+		published := make(chan int)
+		go func() {
+			published <- tp.Publish(1)
+		}()
+		assert.Eventually(t, func() bool { return len(fast.Peek()) == 2 }, mustTimeout, mustRate)
+		subscribed := make(chan topic.Subscription)
+		go func() {
+			subscribed <- tp.Subscribe(infinite.New(1))
+		}()
+		select {
+		case <-time.After(mustTimeout):
+			assert.Fail(t, "unable to subscribe while publish is blocked")
+		case s := <-subscribed:
+			assert.Equal(t, 3, tp.Subscribers())
+			defer s.Unsubscribe()
+		}
+		assert.Equal(t, []interface{}{0}, blocked.Unsubscribe())
+		select {
+		case <-time.After(mustTimeout):
+			assert.Fail(t, "unable to confirm publish")
+		case overflows := <-published:
+			assert.Equal(t, 1, overflows)
+		}
+		assert.Equal(t, 2, tp.Subscribers())
+		assert.Equal(t, []interface{}{0, 1}, fast.Flush())
+	})
+	t.Run("Test Unsubscribe", func(t *testing.T) {
+		//unsubscribe and confirm that the items that weren't dequeued are
+		// returned and that no more items are received
+		tp := topic.New()
+		defer tp.Close()
+		s1, s2 := tp.Subscribe(finite.New(10)), tp.Subscribe(finite.New(10))
+		assert.Zero(t, tp.Publish(1))
+		assert.Zero(t, tp.Publish(2))
+		assert.Equal(t, []interface{}{1, 2}, s1.Unsubscribe())
+		assert.Equal(t, 1, tp.Subscribers())
+		assert.Zero(t, tp.Publish(3))
+		_, underflow := s1.Dequeue()
+		assert.True(t, underflow)
+		assert.Equal(t, []interface{}{1, 2, 3}, s2.Flush())
+		assert.Nil(t, s1.GetSignalIn())
+		assert.Empty(t, s1.Unsubscribe())
+	})
+	t.Run("Test Event", func(t *testing.T) {
+		var wg sync.WaitGroup
+
+		//wait on the signal in of each subscriber and confirm that each
+		// one is signaled once an item is published
+		tp := topic.New()
+		defer tp.Close()
+		subscriptions := []topic.Subscription{
+			tp.Subscribe(finite.New(1)),
+			tp.Subscribe(infinite.New(1)),
+		}
+		for _, s := range subscriptions {
+			wg.Add(1)
+			go func(s topic.Subscription) {
+				defer wg.Done()
+
+				select {
+				case <-time.After(mustTimeout):
+					assert.Fail(t, "no signal received when expected")
+				case <-s.GetSignalIn():
+				}
+				item, underflow := s.Dequeue()
+				assert.False(t, underflow)
+				assert.Equal(t, 1, item)
+			}(s)
+		}
+		//KIM: the infinite queue only signals if someone is listening
+		time.Sleep(10 * mustRate)
+		assert.Zero(t, tp.Publish(1))
+		wg.Wait()
+	})
+	t.Run("Test Close", func(t *testing.T) {
+		//close the topic and confirm that the subscriptions are closed,
+		// publishing doesn't overflow since there aren't any subscribers
+		tp := topic.New()
+		s := tp.Subscribe(finite.New(1))
+		assert.Zero(t, tp.Publish(1))
+		tp.Close()
+		assert.Zero(t, tp.Subscribers())
+		assert.Zero(t, tp.Publish(2))
+		_, underflow := s.Dequeue()
+		assert.True(t, underflow)
+		assert.Empty(t, s.Unsubscribe())
+
+		//confirm that subscribing to a closed topic returns a closed
+		// subscription
+		s = tp.Subscribe(infinite.New(1))
+		assert.Zero(t, tp.Subscribers())
+		assert.Nil(t, s.GetSignalIn())
+		_, underflow = s.Dequeue()
+		assert.True(t, underflow)
+	})
+}
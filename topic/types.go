@@ -0,0 +1,40 @@
+package topic
+
+import (
+	goqueue "github.com/antonio-alexander/go-queue"
+)
+
+//Storage describes the queue that buffers the items for a subscriber,
+// both the finite and infinite queues satisfy this interface; how a full
+// queue is handled (e.g. finite.WithOverflowPolicy()) is up to the queue
+type Storage interface {
+	goqueue.Owner
+	goqueue.Enqueuer
+	goqueue.Dequeuer
+	goqueue.Peeker
+	goqueue.Event
+}
+
+//Subscription can be used to consume the items published to a topic,
+// Unsubscribe() will stop any further items from being received, close
+// the underlying queue and return any items that haven't been dequeued
+type Subscription interface {
+	goqueue.Dequeuer
+	goqueue.Peeker
+	goqueue.Event
+	Unsubscribe() (remainingElements []interface{})
+}
+
+//Publisher can be used to publish an item to every subscriber, overflows
+// is the number of subscribers whose queue overflowed
+type Publisher interface {
+	Publish(item interface{}) (overflows int)
+}
+
+//Subscriber can be used to subscribe to a topic with the given queue, the
+// subscription takes ownership of the queue; items should only be dequeued
+// via the subscription. Subscribers() returns the number of subscriptions
+type Subscriber interface {
+	Subscribe(storage Storage) (subscription Subscription)
+	Subscribers() (n int)
+}
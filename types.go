@@ -1,9 +1,16 @@
 package goqueue
 
 import (
+	"context"
 	"encoding"
+	"errors"
+	"time"
 )
 
+//ErrQueueClosed will be returned by context-aware (blocking) operations
+// if the queue is closed before or while waiting
+var ErrQueueClosed = errors.New("queue closed")
+
 //These types are specifically provided to attempt to communicate support
 // for how queues would be able to store data in a persistent way no matter
 // the data type (empty interface)
@@ -25,8 +32,12 @@ type (
 // and data structures of a queue pointers. The Close() function should
 // ready the underlying pointer for garbage collection and return a slice
 // of any items that remain in the queue
-type Owner interface {
-	Close() (items []interface{})
+type Owner = OwnerOf[interface{}]
+
+//OwnerOf is the type-safe version of Owner, the items that remain
+// in the queue are returned as T rather than empty interface
+type OwnerOf[T any] interface {
+	Close() (items []T)
 }
 
 //GarbageCollecter can be implemented to re-create the underlying pointers
@@ -40,20 +51,28 @@ type GarbageCollecter interface {
 // queue, it can remove one item via Dequeue(), multiple items via
 // DequeueMultiple() or all items using Flush() underflow will be true if
 // the queue is empty
-type Dequeuer interface {
-	Dequeue() (item interface{}, underflow bool)
-	DequeueMultiple(n int) (items []interface{})
-	Flush() (items []interface{})
+type Dequeuer = DequeuerOf[interface{}]
+
+//DequeuerOf is the type-safe version of Dequeuer, if underflow is true
+// the item returned will be the zero value of T
+type DequeuerOf[T any] interface {
+	Dequeue() (item T, underflow bool)
+	DequeueMultiple(n int) (items []T)
+	Flush() (items []T)
 }
 
 //Peeker can be used to non-destructively remove one or more items from
 // the queue, it can remove all items via Peek(), remove an item from the
 // front of the queue via PeekHead() or remove multiple items via
 // PeekFromHead(). Underflow will be true, if the queue is empty
-type Peeker interface {
-	Peek() (items []interface{})
-	PeekHead() (item interface{}, underflow bool)
-	PeekFromHead(n int) (items []interface{})
+type Peeker = PeekerOf[interface{}]
+
+//PeekerOf is the type-safe version of Peeker, if underflow is true
+// the item returned will be the zero value of T
+type PeekerOf[T any] interface {
+	Peek() (items []T)
+	PeekHead() (item T, underflow bool)
+	PeekFromHead(n int) (items []T)
 }
 
 //Enqueuer can be used to put one or more items into the queue
@@ -61,15 +80,80 @@ type Peeker interface {
 // can be used to place multiple items, in the event the queue is full
 // the remaining items will be provided (if applicable) and overflow
 // will be true
-type Enqueuer interface {
-	Enqueue(item interface{}) (overflow bool)
-	EnqueueMultiple(items []interface{}) (itemsRemaining []interface{}, overflow bool)
+type Enqueuer = EnqueuerOf[interface{}]
+
+//EnqueuerOf is the type-safe version of Enqueuer
+type EnqueuerOf[T any] interface {
+	Enqueue(item T) (overflow bool)
+	EnqueueMultiple(items []T) (itemsRemaining []T, overflow bool)
 }
 
 //EnqueueInFronter describes an operation where you enqueue a single item at the
 // front of the queue, if the queue is full overflow will be true
-type EnqueueInFronter interface {
-	EnqueueInFront(item interface{}) (overflow bool)
+type EnqueueInFronter = EnqueueInFronterOf[interface{}]
+
+//EnqueueInFronterOf is the type-safe version of EnqueueInFronter
+type EnqueueInFronterOf[T any] interface {
+	EnqueueInFront(item T) (overflow bool)
+}
+
+//DequeueFromBacker can be used to destructively remove one or more items
+// from the back of the queue (the opposite of EnqueueInFronter), the items
+// from DequeueMultipleBack() are returned in the order they're removed (the
+// last item first); underflow will be true if the queue is empty
+type DequeueFromBacker = DequeueFromBackerOf[interface{}]
+
+//DequeueFromBackerOf is the type-safe version of DequeueFromBacker
+type DequeueFromBackerOf[T any] interface {
+	DequeueBack() (item T, underflow bool)
+	DequeueMultipleBack(n int) (items []T)
+}
+
+//PeekFromTailer can be used to non-destructively look at one or more items
+// at the back of the queue, the items from PeekFromTail() are returned last
+// item first (the order DequeueMultipleBack() would return them); underflow
+// will be true if the queue is empty
+type PeekFromTailer = PeekFromTailerOf[interface{}]
+
+//PeekFromTailerOf is the type-safe version of PeekFromTailer
+type PeekFromTailerOf[T any] interface {
+	PeekTail() (item T, underflow bool)
+	PeekFromTail(n int) (items []T)
+}
+
+//ConditionalDequeuer can be used to destructively remove items from the
+// front of the queue only if a predicate is true for them, the check and the
+// removal are atomic so the item removed is always the item that was checked.
+// DequeueIf() will remove the item at the front if the predicate is true for
+// it, underflow will be true if the queue is empty or the predicate is false
+// (nothing was removed). DequeueWhile() will remove up to max items from the
+// front until the predicate is false. Keep in mind that the predicate is
+// called while the queue is locked, so it must not use the queue
+type ConditionalDequeuer = ConditionalDequeuerOf[interface{}]
+
+//ConditionalDequeuerOf is the type-safe version of ConditionalDequeuer, if
+// underflow is true the item returned will be the zero value of T
+type ConditionalDequeuerOf[T any] interface {
+	DequeueIf(pred func(item T) bool) (item T, underflow bool)
+	DequeueWhile(pred func(item T) bool, max int) (items []T)
+}
+
+//Remover can be used to search for or remove items anywhere in the queue
+// using a predicate, each function is atomic. RemoveIf() will remove (and
+// return) every item the predicate is true for while the remaining items
+// keep their order, Find() will return the first item (closest to the front)
+// the predicate is true for and its index relative to the front and Contains()
+// will return true if the predicate is true for any item. Items removed are
+// counted as dequeued (e.g. by Stats()). Keep in mind that the predicate is
+// called while the queue is locked, so it must not use the queue
+type Remover = RemoverOf[interface{}]
+
+//RemoverOf is the type-safe version of Remover, if ok is false the index
+// will be -1 and the item returned will be the zero value of T
+type RemoverOf[T any] interface {
+	RemoveIf(pred func(item T) bool) (items []T)
+	Find(pred func(item T) bool) (index int, item T, ok bool)
+	Contains(pred func(item T) bool) (ok bool)
 }
 
 //Length can be used to determine how many items are inside a queue at
@@ -85,3 +169,125 @@ type Event interface {
 	GetSignalIn() (signal <-chan struct{})
 	GetSignalOut() (signal <-chan struct{})
 }
+
+//EnqueuerCtx can be used to put one or more items into the queue, blocking
+// until there's room in the queue. EnqueueCtx() will return ctx.Err() if the
+// context is done or ErrQueueClosed if the queue is closed before the item
+// could be enqueued. EnqueueMultipleCtx() will return the items that couldn't
+// be enqueued alongside the error
+type EnqueuerCtx = EnqueuerCtxOf[interface{}]
+
+//EnqueuerCtxOf is the type-safe version of EnqueuerCtx
+type EnqueuerCtxOf[T any] interface {
+	EnqueueCtx(ctx context.Context, item T) (err error)
+	EnqueueMultipleCtx(ctx context.Context, items []T) (itemsRemaining []T, err error)
+}
+
+//DequeuerCtx can be used to destructively remove one or more items from the
+// queue, blocking until the item(s) are available. DequeueCtx() will return
+// ctx.Err() if the context is done or ErrQueueClosed if the queue is closed
+// before an item could be dequeued. DequeueMultipleCtx() will block until n
+// items have been dequeued, the items dequeued before an error occurs will be
+// returned alongside the error
+type DequeuerCtx = DequeuerCtxOf[interface{}]
+
+//DequeuerCtxOf is the type-safe version of DequeuerCtx
+type DequeuerCtxOf[T any] interface {
+	DequeueCtx(ctx context.Context) (item T, err error)
+	DequeueMultipleCtx(ctx context.Context, n int) (items []T, err error)
+}
+
+//PeekerCtx can be used to non-destructively look at one or more items in the
+// queue, blocking until the item(s) are available. PeekHeadCtx() will return
+// ctx.Err() if the context is done or ErrQueueClosed if the queue is closed.
+// PeekFromHeadCtx() will block until there are at least n items in the queue
+// the items available when an error occurs will be returned alongside the error
+type PeekerCtx = PeekerCtxOf[interface{}]
+
+//PeekerCtxOf is the type-safe version of PeekerCtx
+type PeekerCtxOf[T any] interface {
+	PeekHeadCtx(ctx context.Context) (item T, err error)
+	PeekFromHeadCtx(ctx context.Context, n int) (items []T, err error)
+}
+
+//Stats is a snapshot of the operations performed on a queue since it was
+// created. Overflow and Underflow are the number of non-blocking operations
+// that overflowed or underflowed (blocking operations wait instead), the
+// HighWaterMark is the most items that have been in the queue at once and
+// DroppedSignals is the number of signals that couldn't be sent because no
+// one was listening (or the channel was full)
+type Stats struct {
+	Enqueued           uint64
+	Dequeued           uint64
+	Overflow           uint64
+	Underflow          uint64
+	LossyDiscards      uint64
+	HighWaterMark      uint64
+	Resizes            uint64
+	GarbageCollections uint64
+	DroppedSignals     uint64
+}
+
+//Statser can be used to get a snapshot of the operations performed on a
+// queue, it shouldn't block the queue
+type Statser interface {
+	Stats() (stats Stats)
+}
+
+//DefaultChannelRate is how often a channel pump will poll a queue in case a
+// signal was missed if no rate is configured
+const DefaultChannelRate = 10 * time.Millisecond
+
+//ChannelOverflow describes what a channel pump will do if it can't deliver
+// an item because the channel (or queue) is full
+type ChannelOverflow int
+
+const (
+	//ChannelBlock will wait until the item can be delivered
+	ChannelBlock ChannelOverflow = iota
+	//ChannelDrop will discard the item that can't be delivered
+	ChannelDrop
+	//ChannelLossy will discard the oldest item to make room for the item,
+	// for a queue this requires that it implements EnqueueLossy()
+	ChannelLossy
+)
+
+//ChannelOption can be used to configure a channel pump
+type ChannelOption func(*channelConfiguration)
+
+type channelConfiguration struct {
+	overflow ChannelOverflow
+	buffer   int
+	rate     time.Duration
+}
+
+//WithChannelOverflow will configure what a channel pump does if it can't
+// deliver an item, the default is ChannelBlock
+func WithChannelOverflow(overflow ChannelOverflow) ChannelOption {
+	return func(c *channelConfiguration) {
+		c.overflow = overflow
+	}
+}
+
+//WithChannelBuffer will configure the size of the buffer of the channel
+// created by ToChannel(), the default is zero (un-buffered)
+func WithChannelBuffer(size int) ChannelOption {
+	return func(c *channelConfiguration) {
+		if size < 0 {
+			size = 0
+		}
+		c.buffer = size
+	}
+}
+
+//WithChannelRate will configure how often a channel pump polls a queue
+// in case a signal was missed, if the rate isn't greater than zero the
+// DefaultChannelRate is used
+func WithChannelRate(rate time.Duration) ChannelOption {
+	return func(c *channelConfiguration) {
+		if rate <= 0 {
+			rate = DefaultChannelRate
+		}
+		c.rate = rate
+	}
+}
@@ -0,0 +1,9 @@
+// Copyright 2022 antonio-alexander. All rights reserved.
+// Use of this source code is governed by an MIT
+// license that can be found in the LICENSE file.
+
+/*
+	Package worker provides a pool of goroutines that consume items from a
+	queue and call a handler for each item
+*/
+package worker
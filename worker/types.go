@@ -0,0 +1,126 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+//DefaultPollRate is how often idle workers will poll the queue in case a
+// signal was missed if no rate is configured
+const DefaultPollRate = 10 * time.Millisecond
+
+//ErrPanic will be provided to the error handler if the handler panics
+// and panic recovery is enabled, it wraps the recovered value
+var ErrPanic = errors.New("handler panicked")
+
+//ErrRequeueOverflow will be provided to the error handler if an item that
+// failed can't be put back in the queue because the queue is full, the error
+// provided wraps both ErrRequeueOverflow and the error from the handler
+var ErrRequeueOverflow = errors.New("unable to requeue item: overflow")
+
+//ErrMaxRequeues will be provided to the error handler if an item that
+// failed has already been requeued the configured maximum number of times,
+// the error provided wraps both ErrMaxRequeues and the error from the handler
+var ErrMaxRequeues = errors.New("unable to requeue item: max requeues")
+
+//ErrShutdown will be provided to the error handler if an item that failed
+// can't be put back in the queue because Shutdown() has already flushed it,
+// the error provided wraps both ErrShutdown and the error from the handler
+var ErrShutdown = errors.New("unable to requeue item: shutdown")
+
+//Handler is called by a worker for each item dequeued, the context will
+// be done once the item's timeout (if configured) has passed
+type Handler func(ctx context.Context, item interface{}) (err error)
+
+//ErrorHandler is called if the handler fails and the item isn't put back
+// in the queue
+type ErrorHandler func(item interface{}, err error)
+
+//Pool can be used to stop the workers of a pool; Shutdown() stops the
+// workers from dequeuing, waits for the items being handled to finish (or
+// the context to be done) and then returns the items left in the queue
+type Pool interface {
+	Shutdown(ctx context.Context) (remainingElements []interface{}, err error)
+}
+
+//Option can be used to configure a pool on creation
+type Option func(*configuration)
+
+type configuration struct {
+	recoverPanics  bool
+	requeue        bool
+	maxRequeues    int
+	requeueBackoff time.Duration
+	timeout        time.Duration
+	pollRate       time.Duration
+	errorHandler   ErrorHandler
+}
+
+//WithPanicRecovery will configure workers to recover if the handler panics,
+// the panic is treated as an error that wraps ErrPanic
+func WithPanicRecovery() Option {
+	return func(c *configuration) {
+		c.recoverPanics = true
+	}
+}
+
+//WithRequeue will configure workers to put items back at the front of the
+// queue if the handler fails, the queue must implement EnqueueInFronter
+// otherwise the failure is provided to the error handler
+func WithRequeue() Option {
+	return func(c *configuration) {
+		c.requeue = true
+	}
+}
+
+//WithMaxRequeues will configure the maximum number of times an item that
+// fails can be requeued before it's provided to the error handler (the
+// error wraps ErrMaxRequeues); if max isn't greater than zero, items can be
+// requeued any number of times. To count the attempts, the worker keeps
+// the item and handles it again itself rather than putting it back in the
+// queue (the item is only put back in the queue if the pool is shutdown
+// between attempts). This has no effect unless WithRequeue() is configured
+func WithMaxRequeues(max int) Option {
+	return func(c *configuration) {
+		c.maxRequeues = max
+	}
+}
+
+//WithRequeueBackoff will configure how long a worker waits after an item
+// fails before it's requeued, if the backoff isn't greater than zero, the
+// item is requeued immediately. This has no effect unless WithRequeue() is
+// configured
+func WithRequeueBackoff(backoff time.Duration) Option {
+	return func(c *configuration) {
+		c.requeueBackoff = backoff
+	}
+}
+
+//WithTimeout will configure how long the handler has to handle each item,
+// if the timeout isn't greater than zero, items won't have a timeout
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *configuration) {
+		c.timeout = timeout
+	}
+}
+
+//WithPollRate will configure how often idle workers poll the queue in case
+// a signal was missed, if the rate isn't greater than zero the
+// DefaultPollRate is used
+func WithPollRate(rate time.Duration) Option {
+	return func(c *configuration) {
+		if rate <= 0 {
+			rate = DefaultPollRate
+		}
+		c.pollRate = rate
+	}
+}
+
+//WithErrorHandler will configure a function that's called if the handler
+// fails and the item isn't put back in the queue
+func WithErrorHandler(errorHandler ErrorHandler) Option {
+	return func(c *configuration) {
+		c.errorHandler = errorHandler
+	}
+}
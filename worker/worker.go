@@ -0,0 +1,241 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	goqueue "github.com/antonio-alexander/go-queue"
+)
+
+type pool struct {
+	sync.Mutex
+	sync.WaitGroup
+	configuration
+	queue interface {
+		goqueue.Dequeuer
+		goqueue.Event
+	}
+	handler Handler
+	stopper chan struct{}
+
+	//KIM: requeueMutex ensures that an item can't be requeued while
+	// Shutdown() is flushing the queue, once flushed, items that fail
+	// are provided to the error handler instead of being requeued
+	requeueMutex sync.Mutex
+	flushed      bool
+}
+
+//requeueError is provided to the error handler if an item that failed
+// can't be requeued, it wraps both the reason (e.g. ErrMaxRequeues) and
+// the error returned by the handler; Is() is implemented rather than
+// Unwrap() []error so that errors.Is() can find both before Go 1.20
+type requeueError struct {
+	reason error
+	err    error
+}
+
+func (e *requeueError) Error() string {
+	return e.reason.Error() + ": " + e.err.Error()
+}
+
+func (e *requeueError) Is(target error) bool {
+	return errors.Is(e.reason, target)
+}
+
+func (e *requeueError) Unwrap() error {
+	return e.err
+}
+
+//New can be used to create a pool of n workers (goroutines) that consume
+// items from the given queue and call the handler for each item, if n is
+// less than one, it will be one. The workers are started immediately and
+// run until Shutdown() is called
+func New(queue interface {
+	goqueue.Dequeuer
+	goqueue.Event
+}, n int, handler Handler, options ...Option) Pool {
+	p := &pool{
+		configuration: configuration{
+			pollRate: DefaultPollRate,
+		},
+		queue:   queue,
+		handler: handler,
+		stopper: make(chan struct{}),
+	}
+	for _, option := range options {
+		option(&p.configuration)
+	}
+	if n < 1 {
+		n = 1
+	}
+	for i := 0; i < n; i++ {
+		p.launchWorker()
+	}
+	return p
+}
+
+//launchWorker will start a goroutine that dequeues and handles items
+// until the pool is stopped, once stopped, it won't dequeue any more items
+// but will finish handling the current item
+func (p *pool) launchWorker() {
+	p.Add(1)
+	go func() {
+		defer p.Done()
+
+		signalIn := p.queue.GetSignalIn()
+		tPoll := time.NewTicker(p.pollRate)
+		defer tPoll.Stop()
+		for {
+			select {
+			case <-p.stopper:
+				return
+			default:
+			}
+			item, underflow := p.queue.Dequeue()
+			if !underflow {
+				p.handle(item)
+				continue
+			}
+			select {
+			case <-p.stopper:
+				return
+			case _, ok := <-signalIn:
+				if !ok {
+					//KIM: the signal is closed if the queue is closed or
+					// re-sized, if it's the same signal, stop listening
+					// and rely on polling
+					if signal := p.queue.GetSignalIn(); signal != signalIn {
+						signalIn = signal
+					} else {
+						signalIn = nil
+					}
+				}
+			case <-tPoll.C:
+			}
+		}
+	}()
+}
+
+//call will call the handler with a context that's done once the item's
+// timeout has passed, if panic recovery is enabled, a panic is returned
+// as an error
+func (p *pool) call(item interface{}) (err error) {
+	ctx := context.Background()
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+	if p.recoverPanics {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%w: %v", ErrPanic, r)
+			}
+		}()
+	}
+	return p.handler(ctx, item)
+}
+
+//handle will call the handler for the item, if it fails the item will be
+// put back at the front of the queue (if configured) or provided to the
+// error handler. If the max requeues is configured, the item is handled
+// again by this worker until it succeeds or the max is reached
+func (p *pool) handle(item interface{}) {
+	for requeues := 0; ; requeues++ {
+		err := p.call(item)
+		if err == nil {
+			return
+		}
+		queue, ok := p.queue.(goqueue.EnqueueInFronter)
+		if !p.requeue || !ok {
+			p.fail(item, err)
+			return
+		}
+		if p.maxRequeues > 0 && requeues >= p.maxRequeues {
+			p.fail(item, &requeueError{reason: ErrMaxRequeues, err: err})
+			return
+		}
+		if stopped := p.backoff(); stopped || p.maxRequeues <= 0 {
+			p.requeueItem(queue, item, err)
+			return
+		}
+	}
+}
+
+//backoff will wait for the requeue backoff (if configured) and returns
+// true if the pool was stopped
+func (p *pool) backoff() (stopped bool) {
+	if p.requeueBackoff <= 0 {
+		select {
+		default:
+			return false
+		case <-p.stopper:
+			return true
+		}
+	}
+	tBackoff := time.NewTimer(p.requeueBackoff)
+	defer tBackoff.Stop()
+	select {
+	case <-tBackoff.C:
+		return false
+	case <-p.stopper:
+		return true
+	}
+}
+
+//requeueItem will put the item back at the front of the queue, if the
+// queue is full or has already been flushed by Shutdown(), the item is
+// provided to the error handler
+func (p *pool) requeueItem(queue goqueue.EnqueueInFronter, item interface{}, err error) {
+	p.requeueMutex.Lock()
+	switch {
+	case p.flushed:
+		err = &requeueError{reason: ErrShutdown, err: err}
+	case queue.EnqueueInFront(item):
+		err = &requeueError{reason: ErrRequeueOverflow, err: err}
+	default:
+		err = nil
+	}
+	p.requeueMutex.Unlock()
+	if err != nil {
+		p.fail(item, err)
+	}
+}
+
+//fail will provide the item to the error handler (if configured)
+func (p *pool) fail(item interface{}, err error) {
+	if p.errorHandler != nil {
+		p.errorHandler(item, err)
+	}
+}
+
+func (p *pool) Shutdown(ctx context.Context) (remainingElements []interface{}, err error) {
+	p.Lock()
+	defer p.Unlock()
+
+	select {
+	default:
+		close(p.stopper)
+	case <-p.stopper:
+	}
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		p.Wait()
+	}()
+	select {
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-stopped:
+	}
+	p.requeueMutex.Lock()
+	p.flushed = true
+	remainingElements = p.queue.Flush()
+	p.requeueMutex.Unlock()
+
+	return
+}
@@ -0,0 +1,351 @@
+package worker_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	finite "github.com/antonio-alexander/go-queue/finite"
+	infinite "github.com/antonio-alexander/go-queue/infinite"
+	worker "github.com/antonio-alexander/go-queue/worker"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	mustTimeout = time.Second
+	mustRate    = time.Millisecond
+)
+
+var errFailure = errors.New("failure")
+
+//errorHandler can be used to record the items that have failed
+type errorHandler struct {
+	sync.Mutex
+	items  []interface{}
+	errors []error
+}
+
+func (e *errorHandler) handle(item interface{}, err error) {
+	e.Lock()
+	defer e.Unlock()
+	e.items = append(e.items, item)
+	e.errors = append(e.errors, err)
+}
+
+func (e *errorHandler) get() ([]interface{}, []error) {
+	e.Lock()
+	defer e.Unlock()
+	return append([]interface{}{}, e.items...), append([]error{}, e.errors...)
+}
+
+func TestPool(t *testing.T) {
+	t.Run("Test Concurrency", func(t *testing.T) {
+		const nWorkers, nItems = 4, 100
+
+		var mutex sync.Mutex
+		var once sync.Once
+		var active, maxActive int32
+		var handled []int
+
+		//enqueue items and confirm that each item is handled once, that
+		// n items are handled at once (the handlers wait until all of the
+		// workers are active) and that no more than n are
+		q := finite.New(nItems)
+		defer q.Close()
+		allActive := make(chan struct{})
+		p := worker.New(q, nWorkers, func(ctx context.Context, item interface{}) error {
+			n := atomic.AddInt32(&active, 1)
+			defer atomic.AddInt32(&active, -1)
+			for {
+				max := atomic.LoadInt32(&maxActive)
+				if n <= max || atomic.CompareAndSwapInt32(&maxActive, max, n) {
+					break
+				}
+			}
+			if n == nWorkers {
+				once.Do(func() { close(allActive) })
+			}
+			select {
+			case <-time.After(mustTimeout):
+			case <-allActive:
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			handled = append(handled, item.(int))
+			return nil
+		})
+		for i := 0; i < nItems; i++ {
+			assert.False(t, q.Enqueue(i))
+		}
+		assert.Eventually(t, func() bool {
+			mutex.Lock()
+			defer mutex.Unlock()
+			return len(handled) == nItems
+		}, mustTimeout, mustRate)
+		remaining, err := p.Shutdown(context.TODO())
+		assert.Nil(t, err)
+		assert.Empty(t, remaining)
+		sort.Ints(handled)
+		for i := 0; i < nItems; i++ {
+			assert.Equal(t, i, handled[i])
+		}
+		assert.Equal(t, int32(nWorkers), atomic.LoadInt32(&maxActive))
+	})
+	t.Run("Test Infinite", func(t *testing.T) {
+		var handled int32
+
+		//confirm that items are handled from an infinite queue
+		q := infinite.New(10)
+		defer q.Close()
+		p := worker.New(q, 2, func(ctx context.Context, item interface{}) error {
+			atomic.AddInt32(&handled, 1)
+			return nil
+		})
+		for i := 0; i < 10; i++ {
+			assert.False(t, q.Enqueue(i))
+		}
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(&handled) == 10 }, mustTimeout, mustRate)
+		remaining, err := p.Shutdown(context.TODO())
+		assert.Nil(t, err)
+		assert.Empty(t, remaining)
+	})
+	t.Run("Test Panic Recovery", func(t *testing.T) {
+		//panic while handling an item and confirm that the panic is
+		// provided to the error handler and the worker continues
+		e := &errorHandler{}
+		q := finite.New(10)
+		defer q.Close()
+		p := worker.New(q, 1, func(ctx context.Context, item interface{}) error {
+			if item.(int) == 0 {
+				panic("panic")
+			}
+			return nil
+		}, worker.WithPanicRecovery(), worker.WithErrorHandler(e.handle))
+		assert.False(t, q.Enqueue(0))
+		assert.False(t, q.Enqueue(1))
+		assert.Eventually(t, func() bool { return q.Length() == 0 }, mustTimeout, mustRate)
+		_, err := p.Shutdown(context.TODO())
+		assert.Nil(t, err)
+		items, errs := e.get()
+		assert.Equal(t, []interface{}{0}, items)
+		if assert.Len(t, errs, 1) {
+			assert.ErrorIs(t, errs[0], worker.ErrPanic)
+		}
+	})
+	t.Run("Test Requeue", func(t *testing.T) {
+		var attempts int32
+
+		//fail the first attempts to handle an item and confirm that
+		// the item is put back in the queue and handled again
+		e := &errorHandler{}
+		q := finite.New(10)
+		defer q.Close()
+		p := worker.New(q, 1, func(ctx context.Context, item interface{}) error {
+			if atomic.AddInt32(&attempts, 1) < 3 {
+				return errFailure
+			}
+			return nil
+		}, worker.WithRequeue(), worker.WithErrorHandler(e.handle))
+		assert.False(t, q.Enqueue(0))
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(&attempts) == 3 }, mustTimeout, mustRate)
+		remaining, err := p.Shutdown(context.TODO())
+		assert.Nil(t, err)
+		assert.Empty(t, remaining)
+		items, _ := e.get()
+		assert.Empty(t, items)
+	})
+	t.Run("Test Requeue Overflow", func(t *testing.T) {
+		//fill the queue while the item is being handled and confirm
+		// that the failure is provided to the error handler
+		e := &errorHandler{}
+		q := finite.New(1)
+		defer q.Close()
+		handling := make(chan struct{})
+		p := worker.New(q, 1, func(ctx context.Context, item interface{}) error {
+			if item.(int) == 0 {
+				close(handling)
+				assert.Eventually(t, func() bool { return q.Length() == 1 }, mustTimeout, mustRate)
+				return errFailure
+			}
+			return nil
+		}, worker.WithRequeue(), worker.WithErrorHandler(e.handle))
+		assert.False(t, q.Enqueue(0))
+		<-handling
+		assert.False(t, q.Enqueue(1))
+		assert.Eventually(t, func() bool {
+			items, _ := e.get()
+			return len(items) == 1
+		}, mustTimeout, mustRate)
+		_, err := p.Shutdown(context.TODO())
+		assert.Nil(t, err)
+		items, errs := e.get()
+		assert.Equal(t, []interface{}{0}, items)
+		assert.ErrorIs(t, errs[0], worker.ErrRequeueOverflow)
+		assert.ErrorIs(t, errs[0], errFailure)
+	})
+	t.Run("Test Max Requeues", func(t *testing.T) {
+		const maxRequeues = 2
+
+		var attempts int32
+
+		//always fail to handle an item and confirm that it's only requeued
+		// the max number of times before it's provided to the error handler
+		e := &errorHandler{}
+		q := finite.New(10)
+		defer q.Close()
+		p := worker.New(q, 1, func(ctx context.Context, item interface{}) error {
+			atomic.AddInt32(&attempts, 1)
+			return errFailure
+		}, worker.WithRequeue(), worker.WithMaxRequeues(maxRequeues),
+			worker.WithErrorHandler(e.handle))
+		assert.False(t, q.Enqueue([]byte("0")))
+		assert.Eventually(t, func() bool {
+			items, _ := e.get()
+			return len(items) == 1
+		}, mustTimeout, mustRate)
+		remaining, err := p.Shutdown(context.TODO())
+		assert.Nil(t, err)
+		assert.Empty(t, remaining)
+		assert.Equal(t, int32(maxRequeues+1), atomic.LoadInt32(&attempts))
+		items, errs := e.get()
+		assert.Equal(t, []interface{}{[]byte("0")}, items)
+		assert.ErrorIs(t, errs[0], worker.ErrMaxRequeues)
+		assert.ErrorIs(t, errs[0], errFailure)
+	})
+	t.Run("Test Requeue Backoff", func(t *testing.T) {
+		const backoff = 10 * time.Millisecond
+
+		var mutex sync.Mutex
+		var attempts []time.Time
+
+		//fail to handle an item and confirm that the worker waits at least
+		// the backoff between attempts
+		q := finite.New(10)
+		defer q.Close()
+		p := worker.New(q, 1, func(ctx context.Context, item interface{}) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			if attempts = append(attempts, time.Now()); len(attempts) < 3 {
+				return errFailure
+			}
+			return nil
+		}, worker.WithRequeue(), worker.WithRequeueBackoff(backoff))
+		assert.False(t, q.Enqueue(0))
+		assert.Eventually(t, func() bool {
+			mutex.Lock()
+			defer mutex.Unlock()
+			return len(attempts) == 3
+		}, mustTimeout, mustRate)
+		remaining, err := p.Shutdown(context.TODO())
+		assert.Nil(t, err)
+		assert.Empty(t, remaining)
+		mutex.Lock()
+		defer mutex.Unlock()
+		for i := 1; i < len(attempts); i++ {
+			assert.GreaterOrEqual(t, attempts[i].Sub(attempts[i-1]), backoff)
+		}
+	})
+	t.Run("Test Requeue After Shutdown", func(t *testing.T) {
+		//block the worker while handling an item, shutdown with a context
+		// that's already done and then fail the item, confirm that the
+		// item is provided to the error handler rather than being put back
+		// in the flushed queue
+		e := &errorHandler{}
+		q := finite.New(10)
+		defer q.Close()
+		handling, release := make(chan struct{}), make(chan struct{})
+		p := worker.New(q, 1, func(ctx context.Context, item interface{}) error {
+			close(handling)
+			<-release
+			return errFailure
+		}, worker.WithRequeue(), worker.WithErrorHandler(e.handle))
+		assert.False(t, q.Enqueue(0))
+		<-handling
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		remaining, err := p.Shutdown(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, remaining)
+		close(release)
+		assert.Eventually(t, func() bool {
+			items, _ := e.get()
+			return len(items) == 1
+		}, mustTimeout, mustRate)
+		_, err = p.Shutdown(context.TODO())
+		assert.Nil(t, err)
+		assert.Zero(t, q.Length())
+		items, errs := e.get()
+		assert.Equal(t, []interface{}{0}, items)
+		assert.ErrorIs(t, errs[0], worker.ErrShutdown)
+		assert.ErrorIs(t, errs[0], errFailure)
+	})
+	t.Run("Test Timeout", func(t *testing.T) {
+		const timeout = 10 * time.Millisecond
+
+		//handle an item until its context is done and confirm that the
+		// timeout is provided to the error handler
+		e := &errorHandler{}
+		q := finite.New(1)
+		defer q.Close()
+		p := worker.New(q, 1, func(ctx context.Context, item interface{}) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, worker.WithTimeout(timeout), worker.WithErrorHandler(e.handle))
+		assert.False(t, q.Enqueue(0))
+		assert.Eventually(t, func() bool {
+			items, _ := e.get()
+			return len(items) == 1
+		}, mustTimeout, mustRate)
+		_, err := p.Shutdown(context.TODO())
+		assert.Nil(t, err)
+		_, errs := e.get()
+		assert.ErrorIs(t, errs[0], context.DeadlineExceeded)
+	})
+	t.Run("Test Shutdown", func(t *testing.T) {
+		const nWorkers = 2
+
+		var handled int32
+
+		//block the workers while handling items and shutdown, confirm
+		// that the in-flight items finish and the remaining items are
+		// returned
+		q := finite.New(10)
+		defer q.Close()
+		release := make(chan struct{})
+		p := worker.New(q, nWorkers, func(ctx context.Context, item interface{}) error {
+			<-release
+			atomic.AddInt32(&handled, 1)
+			return nil
+		})
+		for i := 0; i < 5; i++ {
+			assert.False(t, q.Enqueue(i))
+		}
+		assert.Eventually(t, func() bool { return q.Length() == 5-nWorkers }, mustTimeout, mustRate)
+
+		//confirm that shutdown returns the context error if the in-flight
+		// items don't finish in time
+		ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+		defer cancel()
+		remaining, err := p.Shutdown(ctx)
+		cancel()
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, []interface{}{2, 3, 4}, remaining)
+
+		//release the workers and confirm that shutdown waits for them
+		close(release)
+		remaining, err = p.Shutdown(context.TODO())
+		assert.Nil(t, err)
+		assert.Empty(t, remaining)
+		assert.Equal(t, int32(nWorkers), atomic.LoadInt32(&handled))
+
+		//confirm that enqueued items aren't handled once shutdown
+		assert.False(t, q.Enqueue(5))
+		time.Sleep(10 * time.Millisecond)
+		assert.Equal(t, 1, q.Length())
+	})
+}